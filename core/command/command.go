@@ -0,0 +1,306 @@
+// Package command runs the grid commands typed in the ui prompt or given
+// on the command line, like "resize 48 32" or "fill euclid".
+package command
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"signls/core/common"
+	"signls/core/field"
+	"signls/core/music"
+	"signls/filesystem"
+)
+
+const (
+	maxGridSize = 512
+	maxChannel  = 16
+)
+
+// Env is the state the commands act on.
+type Env struct {
+	Grid *field.Grid
+	Bank *filesystem.Bank
+
+	// Cursor position and selection end, where nodes are added and edited.
+	X, Y       int
+	EndX, EndY int
+	// Nodes selected by query instead of the cursor, or nil.
+	Query *field.Query
+
+	// Bank index of the grid to load after the command, -1 for none.
+	Load int
+	// Set once the grid is resized, so that it keeps its size.
+	Resized bool
+}
+
+// NewEnv returns an environment with the cursor on the top left cell.
+func NewEnv(grid *field.Grid, bank *filesystem.Bank) *Env {
+	return &Env{Grid: grid, Bank: bank, Load: -1}
+}
+
+// nodes returns the selected nodes.
+func (e *Env) nodes() []common.Node {
+	if e.Query != nil {
+		return e.Grid.Select(*e.Query)
+	}
+	nodes := []common.Node{}
+	for y := e.Y; y <= e.EndY; y++ {
+		for x := e.X; x <= e.EndX; x++ {
+			if n := e.Grid.Node(x, y); n != nil {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	return nodes
+}
+
+// Command is a grid operation.
+type Command struct {
+	Name string
+	Args []string // Argument names, shown in the usage.
+	Help string
+
+	values func(e *Env, arg int) []string // Completion values of an argument.
+	run    func(e *Env, args []string) error
+}
+
+// Usage returns the command name followed by its arguments.
+func (c Command) Usage() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Commands returns all the commands.
+func Commands() []Command {
+	return []Command{
+		{
+			Name: "resize",
+			Args: []string{"WIDTH", "HEIGHT"},
+			Help: "resize the grid",
+			run:  resize,
+		},
+		{
+			Name: "tempo",
+			Args: []string{"BPM"},
+			Help: "set the tempo",
+			run:  tempo,
+		},
+		{
+			Name:   "fill",
+			Args:   []string{"NODE"},
+			Help:   "fill the selection with a node type",
+			values: func(e *Env, arg int) []string { return field.NodeTypes() },
+			run:    fill,
+		},
+		{
+			Name: "goto",
+			Args: []string{"X", "Y"},
+			Help: "move the cursor",
+			run:  goTo,
+		},
+		{
+			Name: "channel",
+			Args: []string{"CHANNEL"},
+			Help: "set the midi channel of the selected nodes",
+			run:  channel,
+		},
+		{
+			Name: "save-as",
+			Args: []string{"FILE"},
+			Help: "write the bank to a new file and use it",
+			run:  saveAs,
+		},
+		{
+			Name: "load",
+			Args: []string{"GRID"},
+			Help: "load a bank grid",
+			run:  load,
+		},
+	}
+}
+
+// find returns the command with the given name.
+func find(name string) (Command, bool) {
+	for _, c := range Commands() {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// Run parses and runs a command line. A leading colon is ignored.
+func Run(e *Env, line string) error {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), ":"))
+	if len(fields) == 0 {
+		return errors.New("empty command")
+	}
+	c, ok := find(fields[0])
+	if !ok {
+		return fmt.Errorf("unknown command %s", fields[0])
+	}
+	args := fields[1:]
+	if len(args) != len(c.Args) {
+		return fmt.Errorf("usage: %s", c.Usage())
+	}
+	return c.run(e, args)
+}
+
+// RunScript runs commands separated by semicolons or new lines on a bank,
+// saving the grid after each command.
+func RunScript(e *Env, script string) error {
+	lines := strings.FieldsFunc(script, func(r rune) bool {
+		return r == ';' || r == '\n'
+	})
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if err := Run(e, line); err != nil {
+			return fmt.Errorf("%s: %w", strings.TrimSpace(line), err)
+		}
+		e.Grid.Save(e.Bank)
+		if e.Load >= 0 {
+			e.Grid.Load(e.Load, e.Bank.Grid(e.Load))
+			e.Load = -1
+		}
+		if err := e.Bank.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Complete completes the last word of a command line. It returns the
+// completed line, and the candidates when several values match.
+func Complete(e *Env, line string) (string, []string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasSuffix(line, " ") {
+		fields = append(fields, "")
+	}
+	word := fields[len(fields)-1]
+	prefix := strings.TrimSuffix(line, word)
+
+	var values []string
+	if len(fields) == 1 {
+		for _, c := range Commands() {
+			values = append(values, c.Name)
+		}
+	} else if c, ok := find(fields[0]); ok && c.values != nil && len(fields)-2 < len(c.Args) {
+		values = c.values(e, len(fields)-2)
+	}
+
+	candidates := []string{}
+	for _, v := range values {
+		if strings.HasPrefix(v, word) {
+			candidates = append(candidates, v)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return line, nil
+	case 1:
+		return prefix + candidates[0] + " ", nil
+	}
+	return prefix + commonPrefix(candidates), candidates
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// intArg parses an integer argument within a range.
+func intArg(name, value string, min, max int) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, min, max)
+	}
+	return v, nil
+}
+
+func resize(e *Env, args []string) error {
+	width, err := intArg("width", args[0], 1, maxGridSize)
+	if err != nil {
+		return err
+	}
+	height, err := intArg("height", args[1], 1, maxGridSize)
+	if err != nil {
+		return err
+	}
+	e.Grid.Resize(width, height)
+	e.Resized = true
+	e.X, e.EndX = min(e.X, width-1), min(e.EndX, width-1)
+	e.Y, e.EndY = min(e.Y, height-1), min(e.EndY, height-1)
+	return nil
+}
+
+func tempo(e *Env, args []string) error {
+	bpm, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || bpm < common.TempoMin || bpm > common.TempoMax {
+		return fmt.Errorf("tempo must be between %.f and %.f", common.TempoMin, common.TempoMax)
+	}
+	e.Grid.SetTempo(bpm)
+	return nil
+}
+
+func fill(e *Env, args []string) error {
+	return e.Grid.Fill(args[0], e.X, e.Y, e.EndX, e.EndY)
+}
+
+func goTo(e *Env, args []string) error {
+	x, err := intArg("x", args[0], 0, e.Grid.Width-1)
+	if err != nil {
+		return err
+	}
+	y, err := intArg("y", args[1], 0, e.Grid.Height-1)
+	if err != nil {
+		return err
+	}
+	e.X, e.Y, e.EndX, e.EndY = x, y, x, y
+	e.Query = nil
+	return nil
+}
+
+func channel(e *Env, args []string) error {
+	ch, err := intArg("channel", args[0], 1, maxChannel)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, n := range e.nodes() {
+		if a, ok := n.(music.Audible); ok {
+			a.Note().SetChannel(uint8(ch - 1))
+			count++
+		}
+	}
+	if count == 0 {
+		return errors.New("no node selected")
+	}
+	return nil
+}
+
+func saveAs(e *Env, args []string) error {
+	filename := args[0]
+	if filepath.Ext(filename) == "" {
+		filename += ".json"
+	}
+	return e.Bank.SaveAs(filename)
+}
+
+func load(e *Env, args []string) error {
+	index, err := intArg("grid", args[0], 1, len(e.Bank.Grids))
+	if err != nil {
+		return err
+	}
+	e.Load = index - 1
+	return nil
+}
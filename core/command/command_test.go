@@ -0,0 +1,79 @@
+package command
+
+import (
+	"path/filepath"
+	"testing"
+
+	"signls/core/field"
+	"signls/core/music"
+	"signls/filesystem"
+	"signls/midi"
+)
+
+func TestRunScript(t *testing.T) {
+	dir := t.TempDir()
+	bank := filesystem.New(filepath.Join(dir, "bank.json"))
+	env := NewEnv(field.NewFromBank(bank, &midi.Mock{}), bank)
+
+	err := RunScript(env, "resize 48 32; tempo 97.5\ngoto 2 3; fill euclid; channel 4; load 7; save-as other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := bank.Grid(0)
+	if first.Width != 48 || first.Height != 32 || first.Tempo != 97.5 {
+		t.Fatalf("unexpected grid: %dx%d at %.1f", first.Width, first.Height, first.Tempo)
+	}
+	if len(first.Nodes) != 1 || first.Nodes[0].X != 2 || first.Nodes[0].Y != 3 {
+		t.Fatalf("unexpected nodes: %+v", first.Nodes)
+	}
+	if env.Grid.BankIndex != 6 {
+		t.Fatalf("grid 7 not loaded")
+	}
+	if filesystem.New(filepath.Join(dir, "other.json")).Grid(0).Width != 48 {
+		t.Fatal("bank not saved as other.json")
+	}
+	if err := RunScript(env, "save-as other.json"); err == nil {
+		t.Fatal("existing file overwritten")
+	}
+}
+
+func TestRun(t *testing.T) {
+	bank := filesystem.New(filepath.Join(t.TempDir(), "bank.json"))
+	env := NewEnv(field.NewFromBank(bank, &midi.Mock{}), bank)
+
+	for _, line := range []string{"", "jump 1", "resize 10", "resize 0 10", "tempo fast", "goto 100 0", "fill nothing", "channel 17", "channel 2", "load 33"} {
+		if err := Run(env, line); err == nil {
+			t.Errorf("%q must fail", line)
+		}
+	}
+
+	if err := Run(env, ":fill bang"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(env, "channel 2"); err != nil {
+		t.Fatal(err)
+	}
+	if ch := env.Grid.Node(0, 0).(music.Audible).Note().Channel.Value(); ch != 1 {
+		t.Fatalf("channel %d, want 1", ch)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		line, want string
+		candidates int
+	}{
+		{line: "re", want: "resize "},
+		{line: "", want: "", candidates: 7},
+		{line: "fill e", want: "fill euclid "},
+		{line: "fill ", want: "fill ", candidates: 9},
+		{line: "tempo 9", want: "tempo 9"},
+		{line: "xyz", want: "xyz"},
+	}
+	for _, test := range tests {
+		got, candidates := Complete(nil, test.line)
+		if got != test.want || len(candidates) != test.candidates {
+			t.Errorf("Complete(%q) = %q, %v", test.line, got, candidates)
+		}
+	}
+}
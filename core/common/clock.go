@@ -1,39 +1,55 @@
 package common
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 const (
 	PulsesPerStep       int = 6
 	StepsPerQuarterNote int = 4
+	QuarterNotesPerBar  int = 4
+)
 
-	tempoMin         float64 = 1.0
-	tempoMax         float64 = 300.0
-	updateBufferSize int     = 128
+// Tempo limits in beats per minute.
+const (
+	TempoMin float64 = 1.0
+	TempoMax float64 = 300.0
 )
 
 // clock manages the timing for MIDI playback, using a standard time.Ticker
 // to generate clock pulses. It provides functionality to update the tempo dynamically.
-// The update channel is used for receiving new tempo values and adjusting the ticker accordingly.
+// The tempo is stored right away, and the update channel tells the clock goroutine
+// to adjust the ticker accordingly.
 //
 // Read more: http://midi.teragonaudio.com/tech/midispec/clock.htm
 type Clock struct {
 	ticker       *time.Ticker
-	update       chan float64
+	update       chan struct{}
+	mu           sync.Mutex
 	tempo        float64
 	shouldUpdate bool // Flag to indicate if the ticker should be updated after the next tick.
 }
 
 // setTempo updates the tempo of the clock. It ensures the new tempo is within the defined range.
-// If the tempo is valid, it sends the new tempo to the update channel.
+// If the tempo is valid, it is stored and the ticker is reset after the next tick.
 func (c *Clock) SetTempo(tempo float64) {
-	if tempo > tempoMax || tempo < tempoMin {
+	if tempo > TempoMax || tempo < TempoMin {
 		return
 	}
-	c.update <- tempo
+	c.mu.Lock()
+	c.tempo = tempo
+	c.mu.Unlock()
+	select {
+	case c.update <- struct{}{}:
+	default: // A ticker reset is already pending.
+	}
 }
 
 // Tempo returns the tempo of the clock.
 func (c *Clock) Tempo() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.tempo
 }
 
@@ -43,7 +59,7 @@ func (c *Clock) Tempo() float64 {
 func NewClock(tempo float64, tick func()) *Clock {
 	c := &Clock{
 		ticker: time.NewTicker(newClockInterval(tempo)),
-		update: make(chan float64, updateBufferSize),
+		update: make(chan struct{}, 1),
 		tempo:  tempo,
 	}
 	go func(c *Clock) {
@@ -52,12 +68,11 @@ func NewClock(tempo float64, tick func()) *Clock {
 			case <-c.ticker.C:
 				tick()
 				if c.shouldUpdate {
-					c.ticker.Reset(newClockInterval(c.tempo))
+					c.ticker.Reset(newClockInterval(c.Tempo()))
 					c.shouldUpdate = false
 				}
-			case newTempo := <-c.update:
+			case <-c.update:
 				c.shouldUpdate = true
-				c.tempo = newTempo
 			}
 		}
 	}(c)
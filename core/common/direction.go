@@ -100,3 +100,13 @@ func (d Direction) Symbol() string {
 	}
 	return " "
 }
+
+// DirectionFromSymbol returns the direction matching a string symbol.
+func DirectionFromSymbol(symbol string) (Direction, bool) {
+	for d, s := range symbols {
+		if s == symbol {
+			return d, true
+		}
+	}
+	return NONE, false
+}
@@ -1,6 +1,7 @@
 package field
 
 import (
+	"fmt"
 	"sync"
 
 	"signls/core/common"
@@ -8,6 +9,7 @@ import (
 	"signls/core/music/meta"
 	"signls/core/node"
 	"signls/core/theory"
+	"signls/filesystem"
 	"signls/midi"
 )
 
@@ -15,6 +17,8 @@ const (
 	defaultTempo                = 120.
 	defaultRootKey theory.Key   = 60
 	defaultScale   theory.Scale = theory.CHROMATIC
+
+	noQueuedGrid = -1
 )
 
 // Grid represents the main structure for the grid-based sequencer.
@@ -23,15 +27,35 @@ type Grid struct {
 
 	midi      midi.Midi
 	device    midi.Device
+	bank      *filesystem.Bank
 	clock     *common.Clock
 	nodes     [][]common.Node
 	Height    int
 	Width     int
 	BankIndex int
+	queued    int // Bank index of the grid to load on the next boundary.
+
+	// Bank slot annotations.
+	Name  string
+	Color int
+	Notes string
+
+	parent  *Grid    // Main grid when the grid is a layer.
+	layers  []*Grid  // Grids playing on top of the main grid.
+	noteLog *NoteLog // Notes sent by the main grid and its layers.
+	mixer   *music.Mixer
+	Muted   bool
+	Solo    bool
 
 	Key   theory.Key
 	Scale theory.Scale
 
+	Performance music.Performance
+
+	Progression []Chord
+	chord       int // Index of the chord currently played.
+	chordBars   int // Bars elapsed since the current chord started.
+
 	Playing bool
 
 	SendClock     bool
@@ -39,6 +63,9 @@ type Grid struct {
 
 	pulse uint64 // Global pulse counter for timing events
 
+	arrivals []arrival     // Signals moved during the step.
+	early    []*music.Note // Notes triggered ahead of the next step.
+
 	clipboard [][]common.Node
 }
 
@@ -46,14 +73,21 @@ type Grid struct {
 func NewGrid(width, height int, midi midi.Midi, device string) *Grid {
 	d := midi.NewDevice(device, "")
 	grid := &Grid{
-		midi:   midi,
 		device: d,
 		nodes:  make([][]common.Node, height),
 		Height: height,
 		Width:  width,
 		Key:    defaultRootKey,
 		Scale:  defaultScale,
+		queued: noQueuedGrid,
+		mixer:  music.NewMixer(),
+
+		Performance: music.Performance{
+			BendRange: music.DefaultBendRange,
+		},
+		Progression: newProgression(),
 	}
+	grid.midi = newLayerMidi(midi, grid)
 	for i := range grid.nodes {
 		grid.nodes[i] = make([]common.Node, width)
 	}
@@ -71,9 +105,13 @@ func NewGrid(width, height int, midi midi.Midi, device string) *Grid {
 	return grid
 }
 
-// TogglePlay toggles the playing state of the grid.
+// TogglePlay toggles the playing state of the grid and its layers.
 func (g *Grid) TogglePlay() {
+	g = g.Root()
 	g.Playing = !g.Playing
+	for _, l := range g.layers {
+		l.Playing = g.Playing
+	}
 	if !g.Playing {
 		g.Reset()
 		g.midi.SilenceAll()
@@ -100,16 +138,22 @@ func (g *Grid) Tempo() float64 {
 	return g.clock.Tempo()
 }
 
-// SetKey changes the root key of the grid and transposes all notes accordingly.
+// SetKey changes the root key of the grid and its layers and transposes all
+// notes accordingly.
 func (g *Grid) SetKey(key theory.Key) {
-	g.Key = key
-	g.Transpose()
+	for _, l := range g.Layers() {
+		l.Key = key
+		l.Transpose()
+	}
 }
 
-// SetScale changes the scale of the grid and transposes all notes accordingly.
+// SetScale changes the scale of the grid and its layers and transposes all
+// notes accordingly.
 func (g *Grid) SetScale(scale theory.Scale) {
-	g.Scale = scale
-	g.Transpose()
+	for _, l := range g.Layers() {
+		l.Scale = scale
+		l.Transpose()
+	}
 }
 
 // MidiDevice returns the name of the currently active MIDI device.
@@ -132,6 +176,11 @@ func (g *Grid) Pulse() uint64 {
 	return g.pulse / uint64(common.PulsesPerStep)
 }
 
+// Bar returns the number of bars played since the grid started.
+func (g *Grid) Bar() int {
+	return int(g.pulse / uint64(pulsesPerBar))
+}
+
 // QuarterNote checks if the current pulse aligns with a quarter note.
 func (g *Grid) QuarterNote() bool {
 	if !g.Playing {
@@ -177,6 +226,7 @@ func (g *Grid) Paste(startX, startY, endX, endY int) {
 				continue
 			}
 			g.nodes[startY+y][startX+x] = g.clipboard[y][x].(common.Copyable).Copy(startX+x, startY+y)
+			g.attach(g.nodes[startY+y][startX+x], startX+x, startY+y)
 		}
 	}
 }
@@ -215,6 +265,20 @@ func (g *Grid) AddNodeFromSymbol(symbol string, x, y int) {
 	}
 }
 
+// Fill adds a node of the given type in every cell of a specified region.
+func (g *Grid) Fill(name string, startX, startY, endX, endY int) error {
+	t, ok := filesystem.FindNodeType(name)
+	if !ok {
+		return fmt.Errorf("unknown node %s", name)
+	}
+	for y := startY; y <= endY; y++ {
+		for x := startX; x <= endX; x++ {
+			g.AddNodeFromSymbol(t.Symbol, x, y)
+		}
+	}
+	return nil
+}
+
 // AddNode adds a node to the grid at the specified coordinates.
 func (g *Grid) AddNode(e common.Node, x, y int) {
 	destinationNode, isDestBehavior := g.nodes[y][x].(common.Behavioral)
@@ -223,9 +287,20 @@ func (g *Grid) AddNode(e common.Node, x, y int) {
 		destinationNode.SetBehavior(newNode.Behavior())
 		return
 	}
+	g.attach(e, x, y)
 	g.nodes[y][x] = e
 }
 
+// attach shares the grid performance settings and mixer with an audible
+// node, and gives it a midi interface logging its notes with its position.
+func (g *Grid) attach(n common.Node, x, y int) {
+	if a, ok := n.(music.Audible); ok {
+		a.Note().SetMidi(g.midi.(layerMidi).at(x, y))
+		a.Note().SetPerformance(&g.Performance)
+		a.Note().SetMixer(g.Root().mixer)
+	}
+}
+
 // RemoveNodes removes nodes from a specified region of the grid.
 func (g *Grid) RemoveNodes(startX, startY, endX, endY int) {
 	for y := startY; y <= endY; y++ {
@@ -259,14 +334,81 @@ func (g *Grid) SetAllNodeMutes(mute bool) {
 	}
 }
 
-// Update advances the grid by one step, moving signals and triggering emitters.
+// ToggleNodeSolos toggles the solo state for all nodes in a specified
+// region.
+func (g *Grid) ToggleNodeSolos(startX, startY, endX, endY int) {
+	nodes := []common.Node{}
+	for y := startY; y <= endY; y++ {
+		nodes = append(nodes, g.nodes[y][startX:endX+1]...)
+	}
+	g.ToggleSolos(nodes)
+}
+
+// ToggleSolos solos the given nodes, or unsolos them when they are all
+// soloed.
+func (g *Grid) ToggleSolos(nodes []common.Node) {
+	mixer := g.Mixer()
+	notes := []*music.Note{}
+	soloed := true
+	for _, n := range nodes {
+		if a, ok := n.(music.Audible); ok {
+			notes = append(notes, a.Note())
+			soloed = soloed && mixer.NodeSoloed(a.Note())
+		}
+	}
+	for _, n := range notes {
+		mixer.SetNodeSolo(n, !soloed)
+	}
+	g.retainSolos()
+}
+
+// Mixer returns the mixer shared by the main grid and its layers.
+func (g *Grid) Mixer() *music.Mixer {
+	return g.Root().mixer
+}
+
+// retainSolos forgets the soloed nodes removed from the main grid and its
+// layers.
+func (g *Grid) retainSolos() {
+	mixer := g.Mixer()
+	if !mixer.Active() {
+		return
+	}
+	notes := []*music.Note{}
+	for _, l := range g.Layers() {
+		for y := range l.nodes {
+			for _, n := range l.nodes[y] {
+				if a, ok := n.(music.Audible); ok {
+					notes = append(notes, a.Note())
+				}
+			}
+		}
+	}
+	mixer.RetainNodes(notes)
+}
+
+// Update advances the grid and its layers by one step, moving signals and
+// triggering emitters.
 func (g *Grid) Update() {
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	g.update()
+	g.updateLayers()
+}
+
+func (g *Grid) update() {
 	if g.pulse%uint64(common.PulsesPerStep) != 0 {
 		g.Tick()
 		return
 	}
+	if g.parent == nil {
+		g.advanceSong()
+	}
+	g.loadQueued()
+	if g.parent == nil {
+		g.advanceProgression()
+		g.retainSolos()
+	}
 	for y := g.Height - 1; y >= 0; y-- {
 		for x := g.Width - 1; x >= 0; x-- {
 			if g.nodes[y][x] == nil {
@@ -288,6 +430,7 @@ func (g *Grid) Update() {
 			}
 		}
 	}
+	g.triggerEarly()
 	g.pulse++
 }
 
@@ -314,12 +457,68 @@ func (g *Grid) Transpose() {
 	}
 }
 
-// Reset stops playback and resets the grid to its initial state.
+// Queue queues a bank grid that is loaded on the next quantization
+// boundary while playing.
+func (g *Grid) Queue(index int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.queued = index
+}
+
+// Queued returns the bank index of the queued grid, or -1.
+func (g *Grid) Queued() int {
+	return g.queued
+}
+
+// advanceSong queues the next song grid on bar boundaries, unless a grid
+// is already waiting for the quantization boundary.
+func (g *Grid) advanceSong() {
+	if g.bank == nil || g.queued != noQueuedGrid || g.pulse%uint64(pulsesPerBar) != 0 {
+		return
+	}
+	if index, ok := g.bank.AdvanceSong(g.Bar()); ok {
+		g.queued = index
+	}
+}
+
+// loadQueued loads the queued grid if the current step is on a bank
+// quantization boundary.
+func (g *Grid) loadQueued() {
+	if g.queued == noQueuedGrid || g.bank == nil {
+		return
+	}
+	quantize := uint64(max(g.bank.Quantize, 1) * common.PulsesPerStep)
+	if g.pulse%quantize != 0 {
+		return
+	}
+	index := g.queued
+	g.reset()
+	// Layers keep playing, only the notes of the replaced grid are stopped.
+	g.stopNotes()
+	g.load(index, g.bank.Grid(index))
+	g.Playing = true
+}
+
+// Reset stops playback and resets the grid and its layers to their initial
+// state.
 func (g *Grid) Reset() {
+	g = g.Root()
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	g.reset()
+}
+
+// reset resets the grid and its layers. A layer keeps following the main
+// grid transport. The grid lock must be held.
+func (g *Grid) reset() {
 	g.Playing = false
 	g.pulse = 0
+	if g.parent != nil {
+		g.Playing = g.parent.Playing
+		g.pulse = g.parent.pulse
+	}
+	g.chord = 0
+	g.chordBars = 0
 	for y := 0; y < g.Height; y++ {
 		for x := 0; x < g.Width; x++ {
 			if _, ok := g.nodes[y][x].(common.Movable); ok {
@@ -331,6 +530,11 @@ func (g *Grid) Reset() {
 			}
 		}
 	}
+	for _, l := range g.layers {
+		l.mu.Lock()
+		l.reset()
+		l.mu.Unlock()
+	}
 }
 
 // Emit makes specified emitter generates signals.
@@ -355,6 +559,7 @@ func (g *Grid) Emit(emitter music.Audible, x, y int) {
 			g.Move(n, newX, newY)
 		}
 		g.nodes[newY][newX] = node.NewSignal(direction, g.pulse)
+		g.arrive(newX, newY)
 	}
 }
 
@@ -364,15 +569,19 @@ func (g *Grid) ExecuteMetaCommands(node music.Audible) {
 		if !cmd.Executed() {
 			continue
 		}
+		// Layers share the main grid root key and scale, and switch the
+		// main grid bank slot.
 		switch c := cmd.(type) {
 		case *meta.RootCommand:
-			g.Key = theory.Key(c.Value().Computed())
+			g.Root().Key = theory.Key(c.Value().Computed())
 		case *meta.ScaleCommand:
-			g.Scale = theory.AllScales()[c.Value().Computed()]
+			g.Root().Scale = theory.AllScales()[c.Value().Computed()]
 		case *meta.TempoCommand:
 			g.SetTempo(float64(c.Value().Computed()))
 		case *meta.BankCommand:
-			g.BankIndex = c.Value().Computed()
+			if index := c.Value().Computed(); index != g.Root().BankIndex {
+				g.Root().queued = index
+			}
 		}
 
 		cmd.Reset()
@@ -395,6 +604,7 @@ func (g *Grid) Move(movable common.Movable, x, y int) {
 
 	if g.nodes[newY][newX] == nil {
 		g.nodes[newY][newX] = g.nodes[y][x]
+		g.arrive(newX, newY)
 	} else if n, ok := g.nodes[newY][newX].(common.Behavioral); ok && n.Behavior().ShouldPropagate() {
 		g.PropagateZone(g.nodes[newY][newX].(*node.Emitter), direction, newX, newY)
 	} else if n, ok := g.nodes[newY][newX].(music.Audible); ok {
@@ -405,6 +615,7 @@ func (g *Grid) Move(movable common.Movable, x, y int) {
 	} else if n, ok := g.nodes[newY][newX].(*node.Signal); ok {
 		g.Move(n, newX, newY)
 		g.nodes[newY][newX] = g.nodes[y][x]
+		g.arrive(newX, newY)
 	}
 
 	g.nodes[y][x] = nil
@@ -452,11 +663,63 @@ func (g *Grid) Teleport(t *node.HoleEmitter, m common.Node, x, y int) {
 		g.Teleport(n, m, teleportX, teleportY)
 	} else if g.nodes[teleportY][teleportX] == nil {
 		g.nodes[teleportY][teleportX] = m
+		g.arrive(teleportX, teleportY)
 	}
 }
 
+// arrival is a signal moved during the step.
+type arrival struct {
+	node common.Node
+	x, y int
+}
+
+// arrive records the signal moved to the given position, to trigger
+// ahead the early note it reaches on the next step.
+func (g *Grid) arrive(x, y int) {
+	g.arrivals = append(g.arrivals, arrival{node: g.nodes[y][x], x: x, y: y})
+}
+
+// triggerEarly triggers ahead the early notes of the nodes the moved
+// signals reach on the next step, so that their negative timing offsets
+// are sent before the step without delaying the other notes.
+func (g *Grid) triggerEarly() {
+	// Early triggers of the step that did not happen are dropped.
+	for _, n := range g.early {
+		n.ClearEarly()
+	}
+	g.early = g.early[:0]
+	if len(g.arrivals) == 0 {
+		return
+	}
+
+	key, scale := g.upcomingKey()
+	for _, a := range g.arrivals {
+		if g.nodes[a.y][a.x] != a.node {
+			continue
+		}
+		x, y := a.node.Direction().NextPosition(a.x, a.y)
+		if g.outOfBounds(x, y) {
+			continue
+		}
+		n, ok := g.nodes[y][x].(music.Audible)
+		if !ok || n.Muted() || !n.Note().Early() {
+			continue
+		}
+		n.Note().PlayEarly(key, scale)
+		g.early = append(g.early, n.Note())
+	}
+	g.arrivals = g.arrivals[:0]
+}
+
 // Resize changes the size of the grid and preserves existing nodes within the new dimensions.
 func (g *Grid) Resize(newWidth, newHeight int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.resize(newWidth, newHeight)
+}
+
+// resize resizes the grid. The grid lock must be held.
+func (g *Grid) resize(newWidth, newHeight int) {
 	newNodes := make([][]common.Node, newHeight)
 	for i := range newNodes {
 		newNodes[i] = make([]common.Node, newWidth)
@@ -11,13 +11,22 @@ import (
 	"signls/midi"
 )
 
-func NewFromBank(bankIndex int, grid filesystem.Grid, midi midi.Midi) *Grid {
+// NewFromBank creates a grid from the bank active grid. The grid keeps the
+// bank for loading queued grids.
+func NewFromBank(bank *filesystem.Bank, midi midi.Midi) *Grid {
+	grid := bank.ActiveGrid()
 	newGrid := NewGrid(grid.Width, grid.Height, midi, grid.Device)
-	newGrid.Load(bankIndex, grid)
+	newGrid.bank = bank
+	newGrid.Load(bank.Active, grid)
 	return newGrid
 }
 
 func (g *Grid) Save(bank *filesystem.Bank) {
+	bank.Save(g.BankIndex, g.Export())
+}
+
+// Export returns the serializable grid.
+func (g *Grid) Export() filesystem.Grid {
 	nodes := []filesystem.Node{}
 
 	for y := range g.nodes {
@@ -76,7 +85,19 @@ func (g *Grid) Save(bank *filesystem.Bank) {
 		}
 	}
 
-	bank.Save(filesystem.Grid{
+	progression := make([]filesystem.Chord, len(g.Progression))
+	for i, c := range g.Progression {
+		progression[i] = filesystem.Chord{
+			Key:   uint8(c.Key),
+			Scale: uint16(c.Scale),
+			Bars:  c.Bars,
+		}
+	}
+
+	return filesystem.Grid{
+		Name:          g.Name,
+		Color:         g.Color,
+		Notes:         g.Notes,
 		Nodes:         nodes,
 		Tempo:         g.Tempo(),
 		Height:        g.Height,
@@ -84,26 +105,59 @@ func (g *Grid) Save(bank *filesystem.Bank) {
 		Device:        g.device.Name,
 		Key:           uint8(g.Key),
 		Scale:         uint16(g.Scale),
+		Progression:   progression,
+		Latency:       g.Performance.Latency,
+		Humanize:      g.Performance.Humanize,
+		BendRange:     g.Performance.BendRange,
 		SendClock:     g.SendClock,
 		SendTransport: g.SendTransport,
-	})
+	}
 }
 
 func (g *Grid) Load(index int, grid filesystem.Grid) {
-	g.Reset()
-	g.midi.SilenceAll()
-
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	g.reset()
+	g.midi.SilenceAll()
+	g.load(index, grid)
+}
 
+// load loads a grid from the bank. The grid lock must be held.
+func (g *Grid) load(index int, grid filesystem.Grid) {
 	g.BankIndex = index
+	g.queued = noQueuedGrid
+	g.Name = grid.Name
+	g.Color = grid.Color
+	g.Notes = grid.Notes
 	g.device = g.midi.NewDevice(grid.Device, "")
-	g.clock.SetTempo(grid.Tempo)
+	if g.parent == nil {
+		g.clock.SetTempo(grid.Tempo)
+	}
 	g.Key = theory.Key(grid.Key)
 	g.Scale = theory.Scale(grid.Scale)
 	g.SendClock = grid.SendClock
 	g.SendTransport = grid.SendTransport
-	g.Resize(grid.Width, grid.Height)
+	g.Performance = music.Performance{
+		Latency:   grid.Latency,
+		Humanize:  grid.Humanize,
+		BendRange: grid.BendRange,
+	}
+	if g.Performance.BendRange == 0 {
+		g.Performance.BendRange = music.DefaultBendRange
+	}
+	g.resize(grid.Width, grid.Height)
+
+	g.Progression = newProgression()
+	for i, c := range grid.Progression {
+		if i >= MaxChords {
+			break
+		}
+		g.Progression[i] = Chord{
+			Key:   theory.Key(c.Key),
+			Scale: theory.Scale(c.Scale),
+			Bars:  c.Bars,
+		}
+	}
 
 	g.nodes = make([][]common.Node, g.Height)
 	for i := range g.nodes {
@@ -111,79 +165,146 @@ func (g *Grid) Load(index int, grid filesystem.Grid) {
 	}
 
 	for _, n := range grid.Nodes {
-		var newNode common.Node
-		switch n.Type {
-		case "bang":
-			newNode = node.NewBangEmitter(g.midi, &g.device, common.Direction(n.Direction), true)
-		case "euclid":
-			newNode = node.NewEuclidEmitter(g.midi, &g.device, common.Direction(n.Direction))
-			newNode.(*node.EuclidEmitter).Steps.Set(n.Params["steps"].Value)
-			newNode.(*node.EuclidEmitter).Steps.SetRandomAmount(n.Params["steps"].Amount)
-			newNode.(*node.EuclidEmitter).Triggers.Set(n.Params["triggers"].Value)
-			newNode.(*node.EuclidEmitter).Triggers.SetRandomAmount(n.Params["triggers"].Amount)
-			newNode.(*node.EuclidEmitter).Offset.Set(n.Params["offset"].Value)
-			newNode.(*node.EuclidEmitter).Offset.SetRandomAmount(n.Params["offset"].Amount)
-		case "pass":
-			newNode = node.NewPassEmitter(g.midi, &g.device, common.Direction(n.Direction))
-		case "spread":
-			newNode = node.NewSpreadEmitter(g.midi, &g.device, common.Direction(n.Direction))
-		case "cycle":
-			newNode = node.NewCycleEmitter(g.midi, &g.device, common.Direction(n.Direction))
-			newNode.(common.Behavioral).Behavior().(*node.CycleEmitter).Repeat().Set(n.Params["repeat"].Value)
-			newNode.(common.Behavioral).Behavior().(*node.CycleEmitter).Repeat().SetRandomAmount(n.Params["repeat"].Amount)
-		case "dice":
-			newNode = node.NewDiceEmitter(g.midi, &g.device, common.Direction(n.Direction))
-			newNode.(common.Behavioral).Behavior().(*node.DiceEmitter).Repeat().Set(n.Params["repeat"].Value)
-			newNode.(common.Behavioral).Behavior().(*node.DiceEmitter).Repeat().SetRandomAmount(n.Params["repeat"].Amount)
-		case "toll":
-			newNode = node.NewTollEmitter(g.midi, &g.device, common.Direction(n.Direction))
-			newNode.(common.Behavioral).Behavior().(*node.TollEmitter).Threshold.Set(n.Params["threshold"].Value)
-			newNode.(common.Behavioral).Behavior().(*node.TollEmitter).Threshold.SetRandomAmount(n.Params["threshold"].Amount)
-		case "zone":
-			newNode = node.NewZoneEmitter(g.midi, &g.device, common.Direction(n.Direction))
-		case "hole":
-			newNode = node.NewHoleEmitter(common.Direction(n.Direction), n.X, n.Y, g.Width, g.Height)
-			newNode.(*node.HoleEmitter).DestinationX.Set(n.Params["destinationX"].Value)
-			newNode.(*node.HoleEmitter).DestinationX.SetRandomAmount(n.Params["destinationX"].Amount)
-			newNode.(*node.HoleEmitter).DestinationY.Set(n.Params["destinationY"].Value)
-			newNode.(*node.HoleEmitter).DestinationY.SetRandomAmount(n.Params["destinationY"].Amount)
-		default:
-			log.Printf("cannot load node of type %s", n.Type)
+		newNode := g.newNode(n)
+		if newNode == nil {
 			continue
 		}
+		g.attach(newNode, n.X, n.Y)
+		g.nodes[n.Y][n.X] = newNode
+	}
 
-		if a, ok := newNode.(music.Audible); ok {
-			a.SetMute(n.Muted)
-			a.Note().SetKey(theory.Key(n.Note.Key.Key), g.Key)
-			a.Note().Key.SetRandomAmount(n.Note.Key.Amount)
-			a.Note().Key.SetSilent(n.Note.Key.Silent)
-			a.Note().Channel.Set(uint8(n.Note.Channel.Value))
-			a.Note().Channel.SetRandomAmount(n.Note.Channel.Amount)
-			a.Note().Velocity.Set(uint8(n.Note.Velocity.Value))
-			a.Note().Velocity.SetRandomAmount(n.Note.Velocity.Amount)
-			a.Note().Length.Set(uint8(n.Note.Length.Value))
-			a.Note().Length.SetRandomAmount(n.Note.Length.Amount)
-			a.Note().Probability = uint8(n.Note.Probability)
-
-			device := g.midi.NewDevice(n.Device, g.device.Name)
-			a.Note().Device.Device = device
-			a.Note().Device.Enabled = device.Enabled()
-
-			for i, c := range n.Note.Controls {
-				a.Note().Controls[i].Type = music.ControlType(c.Type)
-				a.Note().Controls[i].Controller = uint8(c.Controller)
-				a.Note().Controls[i].Value.Set(uint8(c.Value.Value))
-				a.Note().Controls[i].Value.SetRandomAmount(c.Value.Amount)
-			}
+	// Layers play in the main grid root key and scale.
+	if g.parent != nil {
+		g.Key, g.Scale = g.parent.Key, g.parent.Scale
+		g.Transpose()
+	}
+}
 
-			for _, c := range a.Note().MetaCommands {
-				cmd := n.Note.MetaCommands[c.Name()]
-				c.SetActive(cmd.Active)
-				c.Value().Set(cmd.Value.Value)
-				c.Value().SetRandomAmount(cmd.Value.Amount)
-			}
+// newNode creates a grid node from a serialized node.
+func (g *Grid) newNode(n filesystem.Node) common.Node {
+	var newNode common.Node
+	switch n.Type {
+	case "bang":
+		newNode = node.NewBangEmitter(g.midi, &g.device, common.Direction(n.Direction), true)
+	case "euclid":
+		newNode = node.NewEuclidEmitter(g.midi, &g.device, common.Direction(n.Direction))
+		newNode.(*node.EuclidEmitter).Steps.Set(n.Params["steps"].Value)
+		newNode.(*node.EuclidEmitter).Steps.SetRandomAmount(n.Params["steps"].Amount)
+		newNode.(*node.EuclidEmitter).Triggers.Set(n.Params["triggers"].Value)
+		newNode.(*node.EuclidEmitter).Triggers.SetRandomAmount(n.Params["triggers"].Amount)
+		newNode.(*node.EuclidEmitter).Offset.Set(n.Params["offset"].Value)
+		newNode.(*node.EuclidEmitter).Offset.SetRandomAmount(n.Params["offset"].Amount)
+	case "pass":
+		newNode = node.NewPassEmitter(g.midi, &g.device, common.Direction(n.Direction))
+	case "spread":
+		newNode = node.NewSpreadEmitter(g.midi, &g.device, common.Direction(n.Direction))
+	case "cycle":
+		newNode = node.NewCycleEmitter(g.midi, &g.device, common.Direction(n.Direction))
+		newNode.(common.Behavioral).Behavior().(*node.CycleEmitter).Repeat().Set(n.Params["repeat"].Value)
+		newNode.(common.Behavioral).Behavior().(*node.CycleEmitter).Repeat().SetRandomAmount(n.Params["repeat"].Amount)
+	case "dice":
+		newNode = node.NewDiceEmitter(g.midi, &g.device, common.Direction(n.Direction))
+		newNode.(common.Behavioral).Behavior().(*node.DiceEmitter).Repeat().Set(n.Params["repeat"].Value)
+		newNode.(common.Behavioral).Behavior().(*node.DiceEmitter).Repeat().SetRandomAmount(n.Params["repeat"].Amount)
+	case "toll":
+		newNode = node.NewTollEmitter(g.midi, &g.device, common.Direction(n.Direction))
+		newNode.(common.Behavioral).Behavior().(*node.TollEmitter).Threshold.Set(n.Params["threshold"].Value)
+		newNode.(common.Behavioral).Behavior().(*node.TollEmitter).Threshold.SetRandomAmount(n.Params["threshold"].Amount)
+	case "zone":
+		newNode = node.NewZoneEmitter(g.midi, &g.device, common.Direction(n.Direction))
+	case "hole":
+		newNode = node.NewHoleEmitter(common.Direction(n.Direction), n.X, n.Y, g.Width, g.Height)
+		newNode.(*node.HoleEmitter).DestinationX.Set(n.Params["destinationX"].Value)
+		newNode.(*node.HoleEmitter).DestinationX.SetRandomAmount(n.Params["destinationX"].Amount)
+		newNode.(*node.HoleEmitter).DestinationY.Set(n.Params["destinationY"].Value)
+		newNode.(*node.HoleEmitter).DestinationY.SetRandomAmount(n.Params["destinationY"].Amount)
+	default:
+		log.Printf("cannot load node of type %s", n.Type)
+		return nil
+	}
+
+	if a, ok := newNode.(music.Audible); ok {
+		a.SetMute(n.Muted)
+		a.Note().SetKey(theory.Key(n.Note.Key.Key), g.Key)
+		a.Note().Key.SetRandomAmount(n.Note.Key.Amount)
+		a.Note().Key.SetSilent(n.Note.Key.Silent)
+		a.Note().Channel.Set(uint8(n.Note.Channel.Value))
+		a.Note().Channel.SetRandomAmount(n.Note.Channel.Amount)
+		a.Note().Velocity.Set(uint8(n.Note.Velocity.Value))
+		a.Note().Velocity.SetRandomAmount(n.Note.Velocity.Amount)
+		a.Note().Release.Set(uint8(n.Note.Release.Value))
+		a.Note().Release.SetRandomAmount(n.Note.Release.Amount)
+		a.Note().Length.Set(n.Note.Length.Value)
+		a.Note().Length.SetRandomAmount(n.Note.Length.Amount)
+		a.Note().Tie = n.Note.Tie
+		a.Note().Timing.Set(n.Note.Timing.Value)
+		a.Note().Timing.SetRandomAmount(n.Note.Timing.Amount)
+		a.Note().Ratchet.Set(max(min(n.Note.Ratchet.Value, music.MaxRatchet), 1))
+		a.Note().Ratchet.SetRandomAmount(n.Note.Ratchet.Amount)
+		a.Note().RatchetRamp = n.Note.RatchetRamp
+		a.Note().Legato = n.Note.Legato
+		a.Note().Glide = n.Note.Glide
+		a.Note().GlideMode = music.GlideMode(n.Note.GlideMode)
+		a.Note().Probability = uint8(n.Note.Probability)
+
+		device := g.midi.NewDevice(n.Device, g.device.Name)
+		a.Note().Device.Device = device
+		a.Note().Device.Enabled = device.Enabled()
+
+		for i, c := range n.Note.Controls {
+			a.Note().Controls[i].SetType(c.Type)
+			a.Note().Controls[i].SetController(c.Controller)
+			a.Note().Controls[i].Value.Set(c.Value.Value)
+			a.Note().Controls[i].Value.SetRandomAmount(c.Value.Amount)
+			a.Note().Controls[i].Shape = music.ControlShape(c.Shape)
+			a.Note().Controls[i].End.Set(c.End.Value)
+			a.Note().Controls[i].Attack = c.Attack
 		}
 
-		g.nodes[n.Y][n.X] = newNode
+		if err := a.Note().SysEx.SetPayload(n.Note.SysEx.Payload); err != nil {
+			log.Printf("cannot load sysex payload %s: %s", n.Note.SysEx.Payload, err)
+		}
+		a.Note().SysEx.Value.Set(n.Note.SysEx.Value.Value)
+		a.Note().SysEx.Value.SetRandomAmount(n.Note.SysEx.Value.Amount)
+
+		for _, c := range a.Note().MetaCommands {
+			cmd := n.Note.MetaCommands[c.Name()]
+			c.SetActive(cmd.Active)
+			c.Value().Set(cmd.Value.Value)
+			c.Value().SetRandomAmount(cmd.Value.Amount)
+		}
+	}
+
+	return newNode
+}
+
+// Import adds the nodes of a serialized grid, with its top left node at
+// the given position. Nodes outside the grid are skipped.
+func (g *Grid) Import(grid filesystem.Grid, x, y int) {
+	if len(grid.Nodes) == 0 {
+		return
+	}
+	minX, minY := grid.Nodes[0].X, grid.Nodes[0].Y
+	for _, n := range grid.Nodes {
+		minX, minY = min(minX, n.X), min(minY, n.Y)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, n := range grid.Nodes {
+		newX, newY := n.X-minX+x, n.Y-minY+y
+		if g.outOfBounds(newX, newY) {
+			continue
+		}
+		newNode := g.newNode(n)
+		if newNode == nil {
+			continue
+		}
+		if c, ok := newNode.(common.Copyable); ok {
+			// Copying moves relative positions, like hole destinations.
+			newNode = c.Copy(newX, newY)
+		}
+		g.attach(newNode, newX, newY)
+		g.nodes[newY][newX] = newNode
 	}
 }
@@ -0,0 +1,60 @@
+package field
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/core/node"
+	"signls/filesystem"
+	"signls/midi"
+)
+
+func TestTextRoundTrip(t *testing.T) {
+	bank := filesystem.New(filepath.Join(t.TempDir(), "bank.json"))
+	grid := NewFromBank(bank, &midi.Mock{})
+	grid.Resize(8, 6)
+	grid.SetTempo(97.5)
+	grid.Name = "breakdown"
+	grid.Color = 3
+	grid.Notes = "keep the \"hats\" low"
+	grid.Progression[1] = Chord{Key: 62, Scale: 2, Bars: 2}
+
+	grid.AddNodeFromSymbol("b", 0, 0)
+	grid.AddNodeFromSymbol("e", 3, 1)
+	grid.AddNodeFromSymbol("h", 5, 5)
+	grid.AddNodeFromSymbol("s", 7, 5)
+	grid.Node(0, 0).(*node.Emitter).SetDirection(common.DOWN | common.RIGHT)
+	grid.Node(3, 1).(*node.EuclidEmitter).Steps.Set(12)
+	grid.Node(7, 5).(music.Audible).SetMute(true)
+	note := grid.Node(0, 0).(music.Audible).Note()
+	note.Velocity.Set(42)
+	note.Tie = true
+	note.Controls[2].SetType(int(music.NRPNControlType))
+	note.Controls[2].SetController(300)
+	note.Controls[3].SetType(int(music.ControlChangeControlType))
+	note.Controls[3].End.Set(20)
+	note.Controls[3].Attack = 10
+	note.SysEx.SetPayload("F0 43 vv F7")
+	grid.Node(5, 5).(*node.HoleEmitter).DestinationX.Set(1)
+
+	exported := grid.Export()
+	text, err := filesystem.EncodeText(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := filesystem.DecodeText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, exported) {
+		t.Fatalf("text round trip differs:\n%s\ngot  %+v\nwant %+v", text, decoded, exported)
+	}
+
+	grid.Load(0, decoded)
+	if !reflect.DeepEqual(grid.Export(), exported) {
+		t.Fatalf("loaded grid differs:\ngot  %+v\nwant %+v", grid.Export(), exported)
+	}
+}
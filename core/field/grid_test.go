@@ -5,10 +5,66 @@ import (
 	"testing"
 
 	"signls/core/common"
+	"signls/core/music"
 	"signls/core/node"
 	"signls/midi"
 )
 
+func TestToggleSolos(t *testing.T) {
+	grid := NewGrid(8, 8, &midi.Mock{}, "")
+	grid.AddNodeFromSymbol("b", 1, 1)
+	grid.AddNodeFromSymbol("b", 3, 1)
+	grid.EnableNoteLog(true)
+
+	grid.ToggleNodeSolos(3, 1, 3, 1)
+	grid.TogglePlay()
+	for i := 0; i < common.PulsesPerStep; i++ {
+		grid.Update()
+	}
+	for _, e := range grid.NoteLog().Events() {
+		if e.X != 3 {
+			t.Fatalf("note sent by a node that is not soloed: %+v", e)
+		}
+	}
+	if len(grid.NoteLog().Events()) == 0 {
+		t.Fatal("soloed node silent")
+	}
+
+	grid.RemoveNodes(3, 1, 3, 1)
+	grid.Update()
+	if grid.Mixer().Active() {
+		t.Fatal("removed node still soloed")
+	}
+}
+
+func TestEarlyTiming(t *testing.T) {
+	grid := NewGrid(8, 8, &midi.Mock{}, "")
+	grid.AddNodeFromSymbol("b", 1, 1)
+	grid.AddNodeFromSymbol("b", 3, 1)
+	grid.AddNodeFromSymbol("s", 1, 3)
+	grid.AddNodeFromSymbol("s", 3, 3)
+	grid.Node(1, 1).SetDirection(common.DOWN)
+	grid.Node(3, 1).SetDirection(common.DOWN)
+	grid.Node(1, 3).(music.Audible).Note().Timing.Set(-5)
+	grid.EnableNoteLog(true)
+
+	grid.TogglePlay()
+	for i := 0; i < 3*common.PulsesPerStep; i++ {
+		grid.Update()
+	}
+	pulses := map[int]uint64{}
+	sent := 0
+	for _, e := range grid.NoteLog().Events() {
+		if e.Y == 3 {
+			pulses[e.X] = e.Pulse
+			sent++
+		}
+	}
+	if sent != 2 || pulses[3] != 2*uint64(common.PulsesPerStep) || pulses[1] != pulses[3]-5 {
+		t.Fatalf("early note not sent 5 pulses ahead of its step: %v", pulses)
+	}
+}
+
 var benchmarks = []struct {
 	size int
 }{
@@ -0,0 +1,229 @@
+package field
+
+import (
+	"signls/core/music"
+	"signls/midi"
+)
+
+// MaxLayers is the maximum number of grids playing on top of the main grid.
+const MaxLayers = 7
+
+// layerMidi gates the midi messages sent by the nodes of a grid depending
+// on the grid mute and solo states. Note offs and system messages always go
+// through to avoid hanging notes. Notes are logged when the note log is
+// enabled, with the position of the node sending them.
+type layerMidi struct {
+	midi.Midi
+	grid *Grid
+	x, y int // Position of the node sending the messages, -1 for the grid.
+}
+
+func newLayerMidi(m midi.Midi, grid *Grid) layerMidi {
+	return layerMidi{Midi: m, grid: grid, x: -1, y: -1}
+}
+
+// at returns the midi interface of the node at the given position.
+func (m layerMidi) at(x, y int) layerMidi {
+	m.x, m.y = x, y
+	return m
+}
+
+func (m layerMidi) NoteOn(device int, channel uint8, note uint8, velocity uint8) {
+	if !m.grid.Audible() {
+		return
+	}
+	m.Midi.NoteOn(device, channel, note, velocity)
+	m.grid.logNoteOn(m.x, m.y, device, channel, note, velocity)
+}
+
+func (m layerMidi) NoteOff(device int, channel uint8, note uint8) {
+	m.Midi.NoteOff(device, channel, note)
+	m.grid.logNoteOff(device, int(channel), int(note))
+}
+
+func (m layerMidi) NoteOffVelocity(device int, channel uint8, note uint8, velocity uint8) {
+	m.Midi.NoteOffVelocity(device, channel, note, velocity)
+	m.grid.logNoteOff(device, int(channel), int(note))
+}
+
+func (m layerMidi) Silence(device int, channel uint8) {
+	m.Midi.Silence(device, channel)
+	m.grid.logNoteOff(device, int(channel), -1)
+}
+
+func (m layerMidi) SilenceAll() {
+	m.Midi.SilenceAll()
+	m.grid.logNoteOff(-1, -1, -1)
+}
+
+func (m layerMidi) ControlChange(device int, channel, controller, value uint8) {
+	if !m.grid.Audible() {
+		return
+	}
+	m.Midi.ControlChange(device, channel, controller, value)
+}
+
+func (m layerMidi) ProgramChange(device int, channel uint8, value uint8) {
+	if !m.grid.Audible() {
+		return
+	}
+	m.Midi.ProgramChange(device, channel, value)
+}
+
+func (m layerMidi) Pitchbend(device int, channel uint8, value int16) {
+	if !m.grid.Audible() {
+		return
+	}
+	m.Midi.Pitchbend(device, channel, value)
+}
+
+func (m layerMidi) AfterTouch(device int, channel uint8, value uint8) {
+	if !m.grid.Audible() {
+		return
+	}
+	m.Midi.AfterTouch(device, channel, value)
+}
+
+func (m layerMidi) PolyAfterTouch(device int, channel uint8, note uint8, value uint8) {
+	if !m.grid.Audible() {
+		return
+	}
+	m.Midi.PolyAfterTouch(device, channel, note, value)
+}
+
+func (m layerMidi) SysEx(device int, data []byte) {
+	if !m.grid.Audible() {
+		return
+	}
+	m.Midi.SysEx(device, data)
+}
+
+// Root returns the main grid, holding the clock and the layers.
+func (g *Grid) Root() *Grid {
+	if g.parent != nil {
+		return g.parent
+	}
+	return g
+}
+
+// IsLayer returns true if the grid plays on top of a main grid.
+func (g *Grid) IsLayer() bool {
+	return g.parent != nil
+}
+
+// Layers returns the main grid followed by all its layers.
+func (g *Grid) Layers() []*Grid {
+	root := g.Root()
+	return append([]*Grid{root}, root.layers...)
+}
+
+// Layer returns the playing grid loaded from the given bank index, or nil.
+func (g *Grid) Layer(index int) *Grid {
+	for _, l := range g.Layers() {
+		if l.BankIndex == index {
+			return l
+		}
+	}
+	return nil
+}
+
+// AddLayer loads a bank grid on top of the main grid. The layer shares the
+// main grid clock, root key and scale, but keeps its own devices and
+// channels.
+func (g *Grid) AddLayer(index int) *Grid {
+	root := g.Root()
+	if root.bank == nil || len(root.layers) >= MaxLayers || root.Layer(index) != nil {
+		return nil
+	}
+
+	grid := root.bank.Grid(index)
+	layer := &Grid{
+		bank:        root.bank,
+		clock:       root.clock,
+		parent:      root,
+		queued:      noQueuedGrid,
+		Progression: newProgression(),
+	}
+	layer.midi = newLayerMidi(root.midi.(layerMidi).Midi, layer)
+
+	layer.mu.Lock()
+	layer.reset()
+	layer.load(index, grid)
+	layer.mu.Unlock()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	root.layers = append(root.layers, layer)
+	return layer
+}
+
+// RemoveLayer stops and removes the layer loaded from the given bank index.
+func (g *Grid) RemoveLayer(index int) {
+	root := g.Root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	for i, l := range root.layers {
+		if l.BankIndex != index {
+			continue
+		}
+		l.mu.Lock()
+		l.Playing = false
+		l.stopNotes()
+		l.mu.Unlock()
+		root.layers = append(root.layers[:i], root.layers[i+1:]...)
+		return
+	}
+}
+
+// ToggleMute toggles the grid mute state.
+func (g *Grid) ToggleMute() {
+	g.Muted = !g.Muted
+}
+
+// ToggleSolo toggles the grid solo state.
+func (g *Grid) ToggleSolo() {
+	g.Solo = !g.Solo
+}
+
+// Audible returns true if the grid is not muted and no other grid is
+// soloed.
+func (g *Grid) Audible() bool {
+	if g.Muted {
+		return false
+	}
+	if g.Solo {
+		return true
+	}
+	for _, l := range g.Layers() {
+		if l.Solo {
+			return false
+		}
+	}
+	return true
+}
+
+// updateLayers advances the layers with the main grid, sharing its root
+// key, scale and transport state.
+func (g *Grid) updateLayers() {
+	for _, l := range g.layers {
+		l.mu.Lock()
+		l.Playing = g.Playing
+		if l.Key != g.Key || l.Scale != g.Scale {
+			l.Key, l.Scale = g.Key, g.Scale
+			l.Transpose()
+		}
+		l.update()
+		l.mu.Unlock()
+	}
+}
+
+// stopNotes stops all the notes playing in the grid.
+func (g *Grid) stopNotes() {
+	for y := range g.nodes {
+		for _, n := range g.nodes[y] {
+			if a, ok := n.(music.Audible); ok {
+				a.Note().Stop()
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package field
+
+import (
+	"path/filepath"
+	"testing"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/core/theory"
+	"signls/filesystem"
+	"signls/midi"
+)
+
+func TestLayers(t *testing.T) {
+	bank := filesystem.New(filepath.Join(t.TempDir(), "bank.json"))
+	grid := NewFromBank(bank, &midi.Mock{})
+
+	layer := grid.AddLayer(1)
+	if layer == nil || len(grid.Layers()) != 2 || layer.Root() != grid {
+		t.Fatalf("layer not added")
+	}
+	if grid.AddLayer(1) != nil || grid.AddLayer(0) != nil {
+		t.Fatalf("a bank grid must play only once")
+	}
+
+	grid.SetKey(62)
+	grid.SetScale(theory.DORIAN)
+	if layer.Key != 62 || layer.Scale != theory.DORIAN {
+		t.Fatalf("layer must share the main grid root and scale")
+	}
+
+	layer.ToggleSolo()
+	if grid.Audible() || !layer.Audible() {
+		t.Fatalf("solo layer must silence the other grids")
+	}
+	layer.ToggleMute()
+	if layer.Audible() {
+		t.Fatalf("muted layer must be silent")
+	}
+
+	grid.TogglePlay()
+	if !layer.Playing {
+		t.Fatalf("layer must follow the main grid transport")
+	}
+	for i := 0; i < 10; i++ {
+		grid.Update()
+	}
+	if layer.pulse != grid.pulse {
+		t.Fatalf("layer pulse %d, want %d", layer.pulse, grid.pulse)
+	}
+
+	grid.RemoveLayer(1)
+	if len(grid.Layers()) != 1 {
+		t.Fatalf("layer not removed")
+	}
+}
+
+func TestSongQueue(t *testing.T) {
+	bank := filesystem.New(filepath.Join(t.TempDir(), "bank.json"))
+	bank.Song.Entries[0] = filesystem.SongEntry{Grid: 2, Bars: 1, Repeat: 1}
+	bank.Song.Entries[1] = filesystem.SongEntry{Grid: 4, Bars: 1, Repeat: 1}
+	grid := NewFromBank(bank, &midi.Mock{})
+	layer := grid.AddLayer(1)
+
+	bank.Song.Start()
+	grid.TogglePlay()
+	grid.Update()
+	if grid.BankIndex != 2 || layer.BankIndex != 1 {
+		t.Fatalf("song must start in the main grid, got %d", grid.BankIndex)
+	}
+	for i := 1; i < pulsesPerBar; i++ {
+		grid.Update()
+	}
+	if grid.BankIndex != 2 {
+		t.Fatalf("next song grid loaded before the bar boundary")
+	}
+	grid.Update()
+	if grid.BankIndex != 4 || layer.BankIndex != 1 {
+		t.Fatalf("next song grid not loaded on the bar boundary, got %d", grid.BankIndex)
+	}
+}
+
+// silenceCounter is a midi mock counting the silence all messages.
+type silenceCounter struct {
+	midi.Mock
+	silenced int
+}
+
+func (m *silenceCounter) SilenceAll() {
+	m.silenced++
+}
+
+func TestLayerBankCommand(t *testing.T) {
+	bank := filesystem.New(filepath.Join(t.TempDir(), "bank.json"))
+	m := &silenceCounter{}
+	grid := NewFromBank(bank, m)
+	layer := grid.AddLayer(1)
+	layer.AddNodeFromSymbol("b", 1, 1)
+	for _, cmd := range layer.Node(1, 1).(music.Audible).Note().MetaCommands {
+		if cmd.Name() == "bank" {
+			cmd.SetActive(true)
+			cmd.Value().Set(3)
+		}
+	}
+
+	m.silenced = 0
+	grid.TogglePlay()
+	for i := 0; i <= common.PulsesPerStep; i++ {
+		grid.Update()
+	}
+	if grid.BankIndex != 3 || layer.BankIndex != 1 {
+		t.Fatalf("bank command of a layer must switch the main grid, got main %d, layer %d", grid.BankIndex, layer.BankIndex)
+	}
+	if m.silenced != 0 {
+		t.Fatalf("loading the main grid must not silence the layers")
+	}
+}
@@ -0,0 +1,120 @@
+package field
+
+import (
+	"sync"
+	"time"
+)
+
+// maxNoteEvents is the number of notes kept by a note log.
+const maxNoteEvents = 256
+
+// NoteEvent is a note sent by a grid node.
+type NoteEvent struct {
+	Time     time.Time
+	Pulse    uint64 // Grid pulse when the note started.
+	Grid     int    // Bank index of the grid that sent the note.
+	X, Y     int    // Node position, -1 when unknown.
+	Device   string
+	Channel  uint8
+	Key      uint8
+	Velocity uint8
+	Length   int // Length in pulses, -1 while the note is held.
+}
+
+// Held returns true if the note is still playing.
+func (e NoteEvent) Held() bool {
+	return e.Length < 0
+}
+
+// NoteLog keeps the last notes sent by a grid and its layers.
+type NoteLog struct {
+	mu     sync.Mutex
+	events []NoteEvent
+}
+
+// Events returns the logged notes, oldest first.
+func (l *NoteLog) Events() []NoteEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]NoteEvent(nil), l.events...)
+}
+
+func (l *NoteLog) noteOn(e NoteEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.events) >= maxNoteEvents {
+		l.events = append(l.events[:0], l.events[1:]...)
+	}
+	l.events = append(l.events, e)
+}
+
+// noteOff ends the held notes matching a device, channel and key. A
+// negative key ends all the notes of the channel, a negative channel all
+// the held notes.
+func (l *NoteLog) noteOff(device string, channel, key int, pulse uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.events {
+		e := &l.events[i]
+		if !e.Held() ||
+			(channel >= 0 && (e.Device != device || int(e.Channel) != channel)) ||
+			(key >= 0 && int(e.Key) != key) {
+			continue
+		}
+		e.Length = int(pulse - e.Pulse)
+	}
+}
+
+// EnableNoteLog starts or stops logging the notes sent by the grid and its
+// layers.
+func (g *Grid) EnableNoteLog(enabled bool) {
+	root := g.Root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	if !enabled {
+		root.noteLog = nil
+		return
+	}
+	if root.noteLog == nil {
+		root.noteLog = &NoteLog{}
+	}
+}
+
+// NoteLog returns the notes log of the grid and its layers, or nil when
+// disabled.
+func (g *Grid) NoteLog() *NoteLog {
+	return g.Root().noteLog
+}
+
+// logNoteOn logs a note sent by the node at the given position.
+func (g *Grid) logNoteOn(x, y, device int, channel, key, velocity uint8) {
+	log := g.Root().noteLog
+	if log == nil {
+		return
+	}
+	e := NoteEvent{
+		Time:     time.Now(),
+		Pulse:    g.pulse,
+		Grid:     g.BankIndex,
+		X:        x,
+		Y:        y,
+		Device:   g.midi.GetDevice(device).Name,
+		Channel:  channel,
+		Key:      key,
+		Velocity: velocity,
+		Length:   -1,
+	}
+	log.noteOn(e)
+}
+
+func (g *Grid) logNoteOff(device, channel, key int) {
+	log := g.Root().noteLog
+	if log == nil {
+		return
+	}
+	name := ""
+	if device >= 0 {
+		name = g.midi.GetDevice(device).Name
+	}
+	log.noteOff(name, channel, key, g.pulse)
+}
@@ -0,0 +1,43 @@
+package field
+
+import (
+	"path/filepath"
+	"testing"
+
+	"signls/core/common"
+	"signls/filesystem"
+	"signls/midi"
+)
+
+func TestNoteLog(t *testing.T) {
+	bank := filesystem.New(filepath.Join(t.TempDir(), "bank.json"))
+	grid := NewFromBank(bank, &midi.Mock{})
+	grid.AddNodeFromSymbol("b", 2, 1)
+	grid.AddNodeFromSymbol("b", 5, 3) // Same key, told apart by position.
+	grid.EnableNoteLog(true)
+
+	grid.TogglePlay()
+	for i := 0; i < 4*common.PulsesPerStep; i++ {
+		grid.Update()
+	}
+
+	events := grid.NoteLog().Events()
+	if len(events) == 0 {
+		t.Fatal("no note logged")
+	}
+	positions := map[[2]int]bool{}
+	for _, e := range events {
+		if e.Held() {
+			t.Fatalf("unexpected note event: %+v", e)
+		}
+		positions[[2]int{e.X, e.Y}] = true
+	}
+	if len(positions) != 2 || !positions[[2]int{2, 1}] || !positions[[2]int{5, 3}] {
+		t.Fatalf("unexpected note positions: %v", positions)
+	}
+
+	grid.EnableNoteLog(false)
+	if grid.NoteLog() != nil {
+		t.Fatal("note log not disabled")
+	}
+}
@@ -0,0 +1,105 @@
+package field
+
+import (
+	"signls/core/common"
+	"signls/core/theory"
+)
+
+const (
+	// MaxChords is the number of chord slots in a grid progression.
+	MaxChords = 8
+
+	pulsesPerBar = common.PulsesPerStep * common.StepsPerQuarterNote * common.QuarterNotesPerBar
+)
+
+// Chord represents a step of the grid chord progression. A chord with
+// no bars is disabled and skipped.
+type Chord struct {
+	Key   theory.Key
+	Scale theory.Scale
+	Bars  int
+}
+
+// Enabled returns true if the chord is part of the progression.
+func (c Chord) Enabled() bool {
+	return c.Bars > 0
+}
+
+func newProgression() []Chord {
+	progression := make([]Chord, MaxChords)
+	for i := range progression {
+		progression[i] = Chord{
+			Key:   defaultRootKey,
+			Scale: defaultScale,
+		}
+	}
+	return progression
+}
+
+// Chord returns the index of the chord currently played.
+func (g *Grid) Chord() int {
+	return g.chord
+}
+
+// advanceProgression moves the chord progression forward on bar
+// boundaries and applies the current chord root and scale.
+func (g *Grid) advanceProgression() {
+	if g.pulse%uint64(pulsesPerBar) != 0 {
+		return
+	}
+
+	if g.pulse == 0 {
+		g.chord = g.nextChord(len(g.Progression) - 1)
+		g.chordBars = 0
+		g.applyChord()
+		return
+	}
+
+	g.chordBars++
+	if g.chordBars < g.Progression[g.chord].Bars {
+		return
+	}
+	g.chord = g.nextChord(g.chord)
+	g.chordBars = 0
+	g.applyChord()
+}
+
+// nextChord returns the index of the next enabled chord after the given
+// one, wrapping around the progression.
+func (g *Grid) nextChord(current int) int {
+	for i := 1; i <= len(g.Progression); i++ {
+		next := (current + i) % len(g.Progression)
+		if g.Progression[next].Enabled() {
+			return next
+		}
+	}
+	return current
+}
+
+// upcomingKey returns the key and scale of the next step, following the
+// progression when a new chord starts on it.
+func (g *Grid) upcomingKey() (theory.Key, theory.Scale) {
+	root := g.Root()
+	next := g.pulse + uint64(common.PulsesPerStep)
+	if next%uint64(pulsesPerBar) != 0 || len(root.Progression) == 0 ||
+		root.chordBars+1 < root.Progression[root.chord].Bars {
+		return g.Key, g.Scale
+	}
+	chord := root.Progression[root.nextChord(root.chord)]
+	if !chord.Enabled() {
+		return g.Key, g.Scale
+	}
+	return chord.Key, chord.Scale
+}
+
+func (g *Grid) applyChord() {
+	chord := g.Progression[g.chord]
+	if !chord.Enabled() {
+		return
+	}
+	if chord.Key == g.Key && chord.Scale == g.Scale {
+		return
+	}
+	g.SetKey(chord.Key)
+	g.SetScale(chord.Scale)
+}
@@ -0,0 +1,43 @@
+package field
+
+import (
+	"path/filepath"
+	"testing"
+
+	"signls/core/theory"
+	"signls/filesystem"
+	"signls/midi"
+)
+
+func TestProgression(t *testing.T) {
+	bank := filesystem.New(filepath.Join(t.TempDir(), "bank.json"))
+	grid := NewFromBank(bank, &midi.Mock{})
+	layer := grid.AddLayer(1)
+	grid.Progression[0] = Chord{Key: 60, Scale: theory.IONIAN, Bars: 1}
+	grid.Progression[2] = Chord{Key: 62, Scale: theory.DORIAN, Bars: 2}
+
+	tests := []struct {
+		bar   int
+		key   theory.Key
+		scale theory.Scale
+	}{
+		{0, 60, theory.IONIAN},
+		{1, 62, theory.DORIAN},
+		{2, 62, theory.DORIAN},
+		{3, 60, theory.IONIAN},
+		{4, 62, theory.DORIAN},
+	}
+	for _, tt := range tests {
+		// The layers are transposed with the main grid, before their
+		// update.
+		for grid.pulse <= uint64(tt.bar*pulsesPerBar) {
+			grid.update()
+		}
+		if grid.Key != tt.key || grid.Scale != tt.scale {
+			t.Fatalf("bar %d: got %s %s, want %s %s", tt.bar, grid.Key.Name(), grid.Scale.Name(), tt.key.Name(), tt.scale.Name())
+		}
+		if layer.Key != tt.key || layer.Scale != tt.scale {
+			t.Fatalf("bar %d: layer not transposed", tt.bar)
+		}
+	}
+}
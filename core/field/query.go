@@ -0,0 +1,123 @@
+package field
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/filesystem"
+)
+
+// Query selects grid nodes by type and properties. A query is a list of
+// space separated terms: node names (any of them matches), muted or
+// unmuted, channel:N and device:NAME (matching part of the device name).
+type Query struct {
+	text    string
+	types   map[string]bool
+	filters []func(n common.Node) bool
+}
+
+// ParseQuery parses a node query.
+func ParseQuery(text string) (Query, error) {
+	q := Query{
+		text:  strings.TrimSpace(text),
+		types: map[string]bool{},
+	}
+	terms := strings.Fields(strings.ToLower(text))
+	if len(terms) == 0 {
+		return Query{}, errors.New("empty query")
+	}
+
+	for _, term := range terms {
+		name, value, hasValue := strings.Cut(term, ":")
+		switch {
+		case !hasValue && isNodeType(term):
+			q.types[term] = true
+		case !hasValue && term == "muted":
+			q.filters = append(q.filters, func(n common.Node) bool {
+				a, ok := n.(music.Audible)
+				return ok && a.Muted()
+			})
+		case !hasValue && term == "unmuted":
+			q.filters = append(q.filters, func(n common.Node) bool {
+				a, ok := n.(music.Audible)
+				return ok && !a.Muted()
+			})
+		case name == "channel" || name == "ch":
+			channel, err := strconv.Atoi(value)
+			if err != nil || channel < 1 || channel > 16 {
+				return Query{}, fmt.Errorf("invalid channel %s", value)
+			}
+			q.filters = append(q.filters, func(n common.Node) bool {
+				a, ok := n.(music.Audible)
+				return ok && int(a.Note().Channel.Value()) == channel-1
+			})
+		case name == "device" || name == "dev":
+			if value == "" {
+				return Query{}, errors.New("empty device name")
+			}
+			q.filters = append(q.filters, func(n common.Node) bool {
+				a, ok := n.(music.Audible)
+				return ok && strings.Contains(strings.ToLower(a.Note().Device.Name()), value)
+			})
+		default:
+			return Query{}, fmt.Errorf("unknown query term %s", term)
+		}
+	}
+	return q, nil
+}
+
+// NodeTypes returns the node names, as used by queries and commands.
+func NodeTypes() []string {
+	names := []string{}
+	for _, t := range filesystem.NodeTypes() {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func isNodeType(name string) bool {
+	_, ok := filesystem.FindNodeType(name)
+	return ok
+}
+
+// String returns the query text.
+func (q Query) String() string {
+	return q.text
+}
+
+// Match returns true if a node matches all the query terms. Signals never
+// match.
+func (q Query) Match(n common.Node) bool {
+	if n == nil {
+		return false
+	}
+	if _, ok := n.(common.Movable); ok {
+		return false
+	}
+	if len(q.types) > 0 && !q.types[n.Name()] {
+		return false
+	}
+	for _, f := range q.filters {
+		if !f(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns the grid nodes matching a query, row by row.
+func (g *Grid) Select(q Query) []common.Node {
+	nodes := []common.Node{}
+	for y := range g.nodes {
+		for _, n := range g.nodes[y] {
+			if q.Match(n) {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	return nodes
+}
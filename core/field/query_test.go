@@ -0,0 +1,49 @@
+package field
+
+import (
+	"path/filepath"
+	"testing"
+
+	"signls/core/music"
+	"signls/filesystem"
+	"signls/midi"
+)
+
+func TestQuery(t *testing.T) {
+	bank := filesystem.New(filepath.Join(t.TempDir(), "bank.json"))
+	grid := NewFromBank(bank, &midi.Mock{})
+	grid.AddNodeFromSymbol("b", 0, 0)
+	grid.AddNodeFromSymbol("e", 1, 0)
+	grid.AddNodeFromSymbol("e", 2, 0)
+	grid.AddNodeFromSymbol("h", 3, 0)
+	grid.Node(1, 0).(music.Audible).Note().Channel.Set(2)
+	grid.Node(2, 0).(music.Audible).SetMute(true)
+
+	tests := []struct {
+		query string
+		count int
+	}{
+		{"euclid", 2},
+		{"bang euclid", 3},
+		{"ch:3", 1},
+		{"euclid muted", 1},
+		{"unmuted", 2},
+		{"hole", 1},
+		{"hole muted", 0},
+	}
+	for _, test := range tests {
+		q, err := ParseQuery(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nodes := grid.Select(q); len(nodes) != test.count {
+			t.Errorf("query %q selected %d nodes, expected %d", test.query, len(nodes), test.count)
+		}
+	}
+
+	for _, query := range []string{"", "channel:17", "device:", "loud"} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("expected an error for query %q", query)
+		}
+	}
+}
@@ -6,13 +6,26 @@ import (
 )
 
 const (
-	defaultController uint8 = 0
-	minController     uint8 = 0
-	maxController     uint8 = 119
+	defaultController int = 0
+	minController     int = 0
+	maxController     int = 119
 
-	defaultControlValue uint8 = 0
-	minControlValue     uint8 = 0
-	maxControlValue     uint8 = 127
+	// 14-bit controllers are sent as a MSB controller (0-31) followed by
+	// its LSB controller (32-63).
+	maxHighResController int = 31
+	lsbControllerOffset  int = 32
+
+	maxNRPNController int = 16383
+
+	defaultControlValue int = 0
+	minControlValue     int = 0
+	maxControlValue     int = 127
+	maxHighResValue     int = 16383
+
+	nrpnMSBController uint8 = 99
+	nrpnLSBController uint8 = 98
+	dataMSBController uint8 = 6
+	dataLSBController uint8 = 38
 
 	defaultPitchBendValue       = 64
 	minPitchBendValue     int16 = -8192
@@ -27,6 +40,9 @@ const (
 	AfterTouchControlType
 	PitchBendControlType
 	ProgramChangeControlType
+	HighResControlChangeControlType
+	NRPNControlType
+	PolyAfterTouchControlType
 )
 
 var AllControlTypes = []ControlType{
@@ -35,14 +51,52 @@ var AllControlTypes = []ControlType{
 	AfterTouchControlType,
 	PitchBendControlType,
 	ProgramChangeControlType,
+	HighResControlChangeControlType,
+	NRPNControlType,
+	PolyAfterTouchControlType,
+}
+
+// ControlShape defines how a control value evolves over the note length.
+type ControlShape int
+
+const (
+	// StepControlShape sends a single value when the note triggers.
+	StepControlShape ControlShape = iota
+	// RampControlShape moves from the value to the end value over the
+	// note length.
+	RampControlShape
+	// EnvelopeControlShape rises from the value to the end value during
+	// the attack, then decays back to the value at the end of the note.
+	EnvelopeControlShape
+)
+
+var AllControlShapes = []ControlShape{
+	StepControlShape,
+	RampControlShape,
+	EnvelopeControlShape,
 }
 
+const (
+	// MaxControlAttack is the maximum envelope attack in percent of the
+	// note length.
+	MaxControlAttack = 100
+
+	defaultControlAttack = 50
+)
+
 type CC struct {
 	midi midi.Midi
 
 	Type       ControlType
-	Controller uint8
-	Value      *common.ControlValue[uint8]
+	Controller int
+	Value      *common.ControlValue[int]
+
+	Shape  ControlShape
+	End    *common.ControlValue[int] // Ramp end or envelope peak value.
+	Attack int                       // Envelope attack in percent of the note length.
+
+	start int // Value computed when the note triggered.
+	sent  int // Last value sent while shaping.
 }
 
 func NewCC(midi midi.Midi, controlType ControlType) *CC {
@@ -50,31 +104,63 @@ func NewCC(midi midi.Midi, controlType ControlType) *CC {
 		midi:       midi,
 		Type:       controlType,
 		Controller: defaultController,
-		Value:      common.NewControlValue[uint8](defaultControlValue, minControlValue, maxControlValue),
+		Value:      common.NewControlValue[int](defaultControlValue, minControlValue, maxControlValue),
+		End:        common.NewControlValue[int](maxControlValue, minControlValue, maxControlValue),
+		Attack:     defaultControlAttack,
 	}
 }
 
 func (c CC) Copy() *CC {
 	newValue := *c.Value
+	newEnd := *c.End
 	return &CC{
 		midi:       c.midi,
 		Type:       c.Type,
 		Controller: c.Controller,
 		Value:      &newValue,
+		Shape:      c.Shape,
+		End:        &newEnd,
+		Attack:     c.Attack,
 	}
 }
 
-func (c *CC) SetController(controller uint8) {
-	if controller < minController || controller > maxController {
+func (c *CC) SetController(controller int) {
+	if controller < minController || controller > c.MaxController() {
 		return
 	}
 	c.Controller = controller
 }
 
+// MaxController returns the highest controller number for the control type.
+func (c CC) MaxController() int {
+	switch c.Type {
+	case HighResControlChangeControlType:
+		return maxHighResController
+	case NRPNControlType:
+		return maxNRPNController
+	default:
+		return maxController
+	}
+}
+
+// HighRes returns true if the control type sends 14-bit values.
+func (c CC) HighRes() bool {
+	return c.Type == HighResControlChangeControlType || c.Type == NRPNControlType
+}
+
 func (c *CC) SetType(t int) {
 	c.Type = ControlType(t)
 	c.Controller = defaultController
 	c.Value.SetRandomAmount(0)
+	if c.HighRes() {
+		c.Value.SetMax(maxHighResValue)
+		c.End.SetMax(maxHighResValue)
+		c.End.Set(maxHighResValue)
+	} else {
+		c.Value.SetMax(maxControlValue)
+		c.End.SetMax(maxControlValue)
+		c.End.Set(maxControlValue)
+	}
 	if c.Type == PitchBendControlType {
 		c.Value.Set(defaultPitchBendValue)
 	} else {
@@ -82,31 +168,98 @@ func (c *CC) SetType(t int) {
 	}
 }
 
-func (c CC) Send(device int, channel uint8) {
+// Shaped returns true if the control value evolves over the note length.
+func (c CC) Shaped() bool {
+	return c.Shape != StepControlShape &&
+		c.Type != SilentControlType &&
+		c.Type != ProgramChangeControlType
+}
+
+// Send sends the control message. Polyphonic after touch applies to the
+// given key.
+func (c *CC) Send(device int, channel uint8, key uint8) {
+	c.start = c.Value.Computed()
+	c.sent = c.start
+	c.send(device, channel, key, c.start)
+}
+
+// Modulate sends the shaped control value for the given pulse of a note
+// of the given length, if it changed since the last message.
+func (c *CC) Modulate(device int, channel uint8, key uint8, pulse, length int) {
+	if !c.Shaped() || length <= 0 || pulse > length {
+		return
+	}
+	value := c.shapeValue(pulse, length)
+	if value == c.sent {
+		return
+	}
+	c.sent = value
+	c.send(device, channel, key, value)
+}
+
+// shapeValue interpolates the control value at a given pulse of the note.
+func (c CC) shapeValue(pulse, length int) int {
+	end := c.End.Value()
+	switch c.Shape {
+	case RampControlShape:
+		return c.start + (end-c.start)*pulse/length
+	case EnvelopeControlShape:
+		attack := length * c.Attack / MaxControlAttack
+		if pulse < attack {
+			return c.start + (end-c.start)*pulse/attack
+		}
+		if decay := length - attack; decay > 0 {
+			return end + (c.start-end)*(pulse-attack)/decay
+		}
+		return end
+	}
+	return c.start
+}
+
+func (c CC) send(device int, channel uint8, key uint8, value int) {
 	switch c.Type {
 	case SilentControlType:
 		return
 	case ControlChangeControlType:
-		c.midi.ControlChange(device, channel, c.Controller, c.Value.Computed())
+		c.midi.ControlChange(device, channel, uint8(c.Controller), uint8(value))
 	case AfterTouchControlType:
-		c.midi.AfterTouch(device, channel, c.Value.Computed())
+		c.midi.AfterTouch(device, channel, uint8(value))
+	case PolyAfterTouchControlType:
+		c.midi.PolyAfterTouch(device, channel, key, uint8(value))
 	case ProgramChangeControlType:
-		c.midi.ProgramChange(device, channel, c.Value.Computed())
+		c.midi.ProgramChange(device, channel, uint8(value))
+	case HighResControlChangeControlType:
+		msb, lsb := split14(value)
+		c.midi.ControlChange(device, channel, uint8(c.Controller), msb)
+		c.midi.ControlChange(device, channel, uint8(c.Controller+lsbControllerOffset), lsb)
+	case NRPNControlType:
+		paramMSB, paramLSB := split14(c.Controller)
+		msb, lsb := split14(value)
+		c.midi.ControlChange(device, channel, nrpnMSBController, paramMSB)
+		c.midi.ControlChange(device, channel, nrpnLSBController, paramLSB)
+		c.midi.ControlChange(device, channel, dataMSBController, msb)
+		c.midi.ControlChange(device, channel, dataLSBController, lsb)
 	case PitchBendControlType:
-		value := 0
-		if c.Value.Computed() != defaultPitchBendValue {
+		if value != defaultPitchBendValue {
 			value = remap(
-				int(c.Value.Computed()),
-				int(minControlValue),
-				int(maxControlValue),
+				value,
+				minControlValue,
+				maxControlValue,
 				int(minPitchBendValue),
 				int(maxPitchBendValue),
 			)
+		} else {
+			value = 0
 		}
 		c.midi.Pitchbend(device, channel, int16(value))
 	}
 }
 
+// split14 splits a 14-bit value into its 7-bit MSB and LSB.
+func split14(value int) (uint8, uint8) {
+	return uint8(value >> 7 & 0x7f), uint8(value & 0x7f)
+}
+
 func remap(value, oldMin, oldMax, newMin, newMax int) int {
 	if value < oldMin {
 		value = oldMin
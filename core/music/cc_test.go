@@ -0,0 +1,63 @@
+package music
+
+import (
+	"reflect"
+	"testing"
+
+	"signls/midi"
+)
+
+func TestControlShape(t *testing.T) {
+	cc := NewCC(&midi.Mock{}, ControlChangeControlType)
+	cc.Value.Set(20)
+	cc.End.Set(100)
+	cc.Attack = 25
+	cc.Send(0, 0, 60)
+
+	tests := []struct {
+		shape ControlShape
+		pulse int
+		want  int
+	}{
+		{StepControlShape, 6, 20},
+		{RampControlShape, 0, 20},
+		{RampControlShape, 6, 60},
+		{RampControlShape, 12, 100},
+		{EnvelopeControlShape, 3, 100},
+		{EnvelopeControlShape, 9, 47},
+		{EnvelopeControlShape, 12, 20},
+	}
+	for _, tt := range tests {
+		cc.Shape = tt.shape
+		if got := cc.shapeValue(tt.pulse, 12); got != tt.want {
+			t.Fatalf("shape %d, pulse %d: got %d, want %d", tt.shape, tt.pulse, got, tt.want)
+		}
+	}
+}
+
+func TestHighResControl(t *testing.T) {
+	tests := []struct {
+		controlType ControlType
+		controller  int
+		value       int
+		want        []string
+	}{
+		{HighResControlChangeControlType, 1, 0, []string{"cc 1 0", "cc 33 0"}},
+		{HighResControlChangeControlType, 1, 8192, []string{"cc 1 64", "cc 33 0"}},
+		{HighResControlChangeControlType, 31, 16383, []string{"cc 31 127", "cc 63 127"}},
+		{NRPNControlType, 0, 16383, []string{"cc 99 0", "cc 98 0", "cc 6 127", "cc 38 127"}},
+		{NRPNControlType, 300, 1000, []string{"cc 99 2", "cc 98 44", "cc 6 7", "cc 38 104"}},
+		{NRPNControlType, 16383, 0, []string{"cc 99 127", "cc 98 127", "cc 6 0", "cc 38 0"}},
+	}
+	for _, tt := range tests {
+		rec := &recorder{}
+		cc := NewCC(rec, ControlChangeControlType)
+		cc.SetType(int(tt.controlType))
+		cc.SetController(tt.controller)
+		cc.Value.Set(tt.value)
+		cc.Send(0, 0, 60)
+		if got := rec.take(); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("type %d, controller %d, value %d: got %v, want %v", tt.controlType, tt.controller, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package music
+
+import (
+	"signls/core/common"
+)
+
+const (
+	// MaxGlide is the maximum glide time in pulses (a quarter note).
+	MaxGlide = common.PulsesPerStep * common.StepsPerQuarterNote
+	// MaxBendRange is the maximum synth pitch bend range in semitones.
+	MaxBendRange = 24
+	// DefaultBendRange is the usual synth pitch bend range in semitones.
+	DefaultBendRange = 2
+
+	portamentoTimeController uint8 = 5
+	portamentoController     uint8 = 65
+	maxBendValue                   = 8191
+)
+
+// GlideMode defines how a note glides from the previous one.
+type GlideMode int
+
+const (
+	// PortamentoGlideMode lets the synth glide using the portamento
+	// time (CC 5) and portamento switch (CC 65) controllers.
+	PortamentoGlideMode GlideMode = iota
+	// PitchBendGlideMode starts the note bent to the previous pitch and
+	// slides the pitch bend back to center.
+	PitchBendGlideMode
+)
+
+// glide prepares the glide from the previous key before a note is sent.
+// Pitch bend slides only start from a note that is still sounding.
+func (n *Note) glide(device int, channel, from, to uint8, sounding bool) {
+	if n.Glide == 0 {
+		if n.portamento {
+			n.midi.ControlChange(device, channel, portamentoController, 0)
+			n.portamento = false
+		}
+		return
+	}
+
+	switch n.GlideMode {
+	case PortamentoGlideMode:
+		n.midi.ControlChange(
+			device,
+			channel,
+			portamentoTimeController,
+			uint8(remap(n.Glide, 0, MaxGlide, minControlValue, maxControlValue)),
+		)
+		if !n.portamento {
+			n.midi.ControlChange(device, channel, portamentoController, uint8(maxControlValue))
+			n.portamento = true
+		}
+	case PitchBendGlideMode:
+		if !sounding {
+			n.stopGlide()
+			return
+		}
+		bendRange := n.performance.bendRange()
+		semitones := max(min(int(from)-int(to), bendRange), -bendRange)
+		n.bend = int16(semitones * maxBendValue / bendRange)
+		n.bendPulse = 0
+		n.midi.Pitchbend(device, channel, n.bend)
+	}
+}
+
+// tickGlide moves the pitch bend slide toward the note pitch.
+func (n *Note) tickGlide() {
+	if n.bend == 0 {
+		return
+	}
+	n.bendPulse++
+	if n.bendPulse >= n.Glide {
+		n.bend = 0
+		n.midi.Pitchbend(n.Device.Get(), n.Channel.Last(), 0)
+		return
+	}
+	n.midi.Pitchbend(
+		n.Device.Get(),
+		n.Channel.Last(),
+		int16(int(n.bend)*(n.Glide-n.bendPulse)/n.Glide),
+	)
+}
+
+// stopGlide recenters the pitch bend of an unfinished slide.
+func (n *Note) stopGlide() {
+	if n.bend == 0 {
+		return
+	}
+	n.bend = 0
+	n.midi.Pitchbend(n.Device.Get(), n.Channel.Last(), 0)
+}
@@ -0,0 +1,78 @@
+package music
+
+import (
+	"reflect"
+	"testing"
+
+	"signls/core/theory"
+	"signls/midi"
+)
+
+func TestLegato(t *testing.T) {
+	rec := &recorder{}
+	note := NewNote(rec, &midi.Device{})
+	note.Legato = true
+	note.SetKey(60, 60)
+
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	rec.take()
+	note.SetKey(64, 60)
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	// The next note starts before the previous one is released.
+	want := []string{"on 64 100", "off 60"}
+	if got := rec.take(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestPortamento(t *testing.T) {
+	rec := &recorder{}
+	note := NewNote(rec, &midi.Device{})
+	note.SetKey(60, 60)
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	rec.take()
+
+	tests := []struct {
+		glide int
+		want  []string
+	}{
+		{MaxGlide / 2, []string{"off 60", "cc 5 63", "cc 65 127", "on 60 100"}},
+		{MaxGlide, []string{"off 60", "cc 5 127", "on 60 100"}},
+		{0, []string{"off 60", "cc 65 0", "on 60 100"}},
+		{0, []string{"off 60", "on 60 100"}},
+	}
+	for i, tt := range tests {
+		note.Glide = tt.glide
+		note.TransposeAndPlay(60, theory.CHROMATIC)
+		if got := rec.take(); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("trigger %d: got %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestPitchBendGlide(t *testing.T) {
+	rec := &recorder{}
+	note := NewNote(rec, &midi.Device{})
+	note.Legato = true
+	note.Glide = 4
+	note.GlideMode = PitchBendGlideMode
+	note.SetKey(60, 60)
+
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	rec.take()
+
+	// The note starts bent down to the previous pitch, a full bend
+	// range away, and slides back to center.
+	note.SetKey(62, 60)
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	for i := 0; i < note.Glide; i++ {
+		note.Tick()
+	}
+	want := []string{
+		"bend -8191", "on 62 100", "off 60",
+		"bend -6143", "bend -4095", "bend -2047", "bend 0",
+	}
+	if got := rec.take(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
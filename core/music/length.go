@@ -0,0 +1,113 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"signls/core/common"
+)
+
+const (
+	pulsesPerQuarterNote = common.PulsesPerStep * common.StepsPerQuarterNote
+	pulsesPerWholeNote   = pulsesPerQuarterNote * common.QuarterNotesPerBar
+
+	// MaxLength is the longest note length in pulses (4 bars).
+	MaxLength = pulsesPerWholeNote * 4
+)
+
+// noteLength is a musical note value expressed in clock pulses.
+type noteLength struct {
+	Name   string
+	Pulses int
+}
+
+// noteLengths lists the musical note values, sorted by length.
+var noteLengths = []noteLength{
+	{"1|32t", pulsesPerWholeNote / 48},
+	{"1|32", pulsesPerWholeNote / 32},
+	{"1|16t", pulsesPerWholeNote / 24},
+	{"1|16", pulsesPerWholeNote / 16},
+	{"1|8t", pulsesPerWholeNote / 12},
+	{"1|16.", pulsesPerWholeNote * 3 / 32},
+	{"1|8", pulsesPerWholeNote / 8},
+	{"1|4t", pulsesPerWholeNote / 6},
+	{"1|8.", pulsesPerWholeNote * 3 / 16},
+	{"1|4", pulsesPerWholeNote / 4},
+	{"1|2t", pulsesPerWholeNote / 3},
+	{"1|4.", pulsesPerWholeNote * 3 / 8},
+	{"1|2", pulsesPerWholeNote / 2},
+	{"1|1t", pulsesPerWholeNote * 2 / 3},
+	{"1|2.", pulsesPerWholeNote * 3 / 4},
+	{"1|1", pulsesPerWholeNote},
+	{"1|1.", pulsesPerWholeNote * 3 / 2},
+	{"2|1", pulsesPerWholeNote * 2},
+	{"3|1", pulsesPerWholeNote * 3},
+	{"4|1", MaxLength},
+}
+
+var noteLengthRegexp = regexp.MustCompile(`^(\d+)[|/](\d+)([.t]?)$`)
+
+// NoteLengthName returns the note value name of a length in pulses, or
+// false if the length does not match any note value.
+func NoteLengthName(pulses int) (string, bool) {
+	for _, l := range noteLengths {
+		if l.Pulses == pulses {
+			return l.Name, true
+		}
+	}
+	return "", false
+}
+
+// NextNoteLength returns the shortest note value longer than the given
+// length in pulses.
+func NextNoteLength(pulses int) int {
+	for _, l := range noteLengths {
+		if l.Pulses > pulses {
+			return l.Pulses
+		}
+	}
+	return noteLengths[len(noteLengths)-1].Pulses
+}
+
+// PreviousNoteLength returns the longest note value shorter than the
+// given length in pulses.
+func PreviousNoteLength(pulses int) int {
+	for i := len(noteLengths) - 1; i >= 0; i-- {
+		if noteLengths[i].Pulses < pulses {
+			return noteLengths[i].Pulses
+		}
+	}
+	return noteLengths[0].Pulses
+}
+
+// ParseNoteLength converts a note value (ex 1|8, 1/4., 1|16t or 2|1) to
+// a length in pulses. Plain integers are read as pulses.
+func ParseNoteLength(input string) (int, error) {
+	if pulses, err := strconv.Atoi(input); err == nil {
+		return pulses, nil
+	}
+
+	matches := noteLengthRegexp.FindStringSubmatch(input)
+	if len(matches) < 4 {
+		return 0, errors.New("invalid note length format")
+	}
+	num, _ := strconv.Atoi(matches[1])
+	den, _ := strconv.Atoi(matches[2])
+	if den == 0 {
+		return 0, errors.New("invalid note length division")
+	}
+
+	pulses := num * pulsesPerWholeNote
+	switch matches[3] {
+	case ".":
+		pulses = pulses * 3 / 2
+	case "t":
+		pulses = pulses * 2 / 3
+	}
+	if pulses%den != 0 {
+		return 0, fmt.Errorf("note length %s is shorter than a pulse", input)
+	}
+	return pulses / den, nil
+}
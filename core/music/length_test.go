@@ -0,0 +1,34 @@
+package music
+
+import "testing"
+
+func TestParseNoteLength(t *testing.T) {
+	tests := []struct {
+		input  string
+		pulses int
+	}{
+		{"1|16", 6},
+		{"1/8", 12},
+		{"1|8.", 18},
+		{"1|8t", 8},
+		{"1|1", 96},
+		{"2|1", 192},
+		{"4|1", MaxLength},
+		{"10", 10},
+	}
+	for _, tt := range tests {
+		pulses, err := ParseNoteLength(tt.input)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.input, err)
+		}
+		if pulses != tt.pulses {
+			t.Fatalf("%s: got %d pulses, want %d", tt.input, pulses, tt.pulses)
+		}
+	}
+
+	for _, input := range []string{"", "1|64", "1|0", "quarter"} {
+		if _, err := ParseNoteLength(input); err == nil {
+			t.Fatalf("%s: expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package music
+
+import "sync"
+
+// Strip is the state of a mixer channel or device.
+type Strip uint8
+
+const (
+	StripOn Strip = iota
+	StripMuted
+	StripSolo
+)
+
+// Mixer mutes and solos the notes of a grid and its layers by node, channel
+// and device, without touching the node mute flags. When nodes, channels or
+// devices are soloed, only the soloed ones play.
+type Mixer struct {
+	mu sync.Mutex
+
+	nodes    map[*Note]bool
+	channels map[uint8]Strip
+	devices  map[int]Strip
+
+	cleared *Mixer // State saved by the last clear, for a restore.
+}
+
+// NewMixer returns a mixer letting all notes play.
+func NewMixer() *Mixer {
+	return &Mixer{
+		nodes:    map[*Note]bool{},
+		channels: map[uint8]Strip{},
+		devices:  map[int]Strip{},
+	}
+}
+
+// Audible returns true if a note can be sent on a device and channel.
+func (m *Mixer) Audible(n *Note, device int, channel uint8) bool {
+	if m == nil {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.nodes) > 0 && !m.nodes[n] {
+		return false
+	}
+	return stripAudible(m.channels, channel) && stripAudible(m.devices, device)
+}
+
+// stripAudible returns true if a strip is not muted and no other strip is
+// soloed.
+func stripAudible[K comparable](strips map[K]Strip, key K) bool {
+	switch strips[key] {
+	case StripMuted:
+		return false
+	case StripSolo:
+		return true
+	}
+	for _, s := range strips {
+		if s == StripSolo {
+			return false
+		}
+	}
+	return true
+}
+
+// NodeSoloed returns true if the node playing a note is soloed.
+func (m *Mixer) NodeSoloed(n *Note) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodes[n]
+}
+
+// SetNodeSolo sets the solo state of the node playing a note.
+func (m *Mixer) SetNodeSolo(n *Note, solo bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if solo {
+		m.nodes[n] = true
+	} else {
+		delete(m.nodes, n)
+	}
+}
+
+// RetainNodes forgets the soloed nodes that are not in a list, once they
+// are removed from the grids.
+func (m *Mixer) RetainNodes(notes []*Note) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.nodes) == 0 {
+		return
+	}
+	kept := make(map[*Note]bool, len(m.nodes))
+	for _, n := range notes {
+		if m.nodes[n] {
+			kept[n] = true
+		}
+	}
+	m.nodes = kept
+}
+
+// Channel returns the state of a midi channel.
+func (m *Mixer) Channel(channel uint8) Strip {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.channels[channel]
+}
+
+// ToggleChannel switches a midi channel between a mute or solo state and
+// the default one.
+func (m *Mixer) ToggleChannel(channel uint8, s Strip) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	toggleStrip(m.channels, channel, s)
+}
+
+// Device returns the state of a midi device.
+func (m *Mixer) Device(device int) Strip {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.devices[device]
+}
+
+// ToggleDevice switches a midi device between a mute or solo state and the
+// default one.
+func (m *Mixer) ToggleDevice(device int, s Strip) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	toggleStrip(m.devices, device, s)
+}
+
+func toggleStrip[K comparable](strips map[K]Strip, key K, s Strip) {
+	if strips[key] == s || s == StripOn {
+		delete(strips, key)
+		return
+	}
+	strips[key] = s
+}
+
+// Active returns true if any node, channel or device is muted or soloed.
+func (m *Mixer) Active() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.active()
+}
+
+func (m *Mixer) active() bool {
+	return len(m.nodes) > 0 || len(m.channels) > 0 || len(m.devices) > 0
+}
+
+// Clear lets all notes play again, saving the mutes and solos for a
+// restore.
+func (m *Mixer) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.active() {
+		return
+	}
+	m.cleared = &Mixer{nodes: m.nodes, channels: m.channels, devices: m.devices}
+	m.nodes = map[*Note]bool{}
+	m.channels = map[uint8]Strip{}
+	m.devices = map[int]Strip{}
+}
+
+// Restore brings back the mutes and solos saved by the last clear. It
+// returns false if there is nothing to restore.
+func (m *Mixer) Restore() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cleared == nil {
+		return false
+	}
+	m.nodes = m.cleared.nodes
+	m.channels = m.cleared.channels
+	m.devices = m.cleared.devices
+	m.cleared = nil
+	return true
+}
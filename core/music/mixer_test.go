@@ -0,0 +1,44 @@
+package music
+
+import "testing"
+
+func TestMixer(t *testing.T) {
+	m := NewMixer()
+	a, b := &Note{}, &Note{}
+	if !m.Audible(a, 0, 0) || m.Active() {
+		t.Fatal("new mixer must let all notes play")
+	}
+
+	m.ToggleChannel(1, StripMuted)
+	if m.Audible(a, 0, 1) || !m.Audible(a, 0, 2) {
+		t.Fatal("muted channel must be silent")
+	}
+	m.ToggleDevice(1, StripSolo)
+	if m.Audible(a, 0, 2) || !m.Audible(a, 1, 2) || m.Audible(a, 1, 1) {
+		t.Fatal("soloed device must silence the other devices")
+	}
+	m.SetNodeSolo(b, true)
+	if m.Audible(a, 1, 2) || !m.Audible(b, 1, 2) {
+		t.Fatal("soloed node must silence the other nodes")
+	}
+
+	m.Clear()
+	if m.Active() || !m.Audible(a, 0, 1) {
+		t.Fatal("cleared mixer must let all notes play")
+	}
+	if !m.Restore() || m.Channel(1) != StripMuted || m.Device(1) != StripSolo || !m.NodeSoloed(b) {
+		t.Fatal("mutes and solos not restored")
+	}
+	if m.Restore() {
+		t.Fatal("mixer restored twice")
+	}
+
+	m.RetainNodes([]*Note{a})
+	if m.NodeSoloed(b) {
+		t.Fatal("removed node still soloed")
+	}
+	m.ToggleChannel(1, StripMuted)
+	if m.Channel(1) != StripOn {
+		t.Fatal("channel mute not toggled")
+	}
+}
@@ -16,15 +16,20 @@ const (
 	defaultKey      theory.Key = 60 // Middle C
 	defaultChannel  uint8      = 0
 	defaultVelocity uint8      = 100
-	defaultLength   uint8      = uint8(common.PulsesPerStep)
+	defaultRelease  uint8      = 0
+	defaultLength   int        = common.PulsesPerStep
 
 	defaultCCNumbers int = 8
 
 	maxVelocity    uint8 = 127
-	maxLength      uint8 = 127 // 127 is treated as infinity
-	minLength      uint8 = 1
+	minLength      int   = 1
 	maxChannel     uint8 = 15
 	maxProbability uint8 = 100
+
+	// MaxRatchet is the maximum number of hits per trigger.
+	MaxRatchet int = 8
+	// MaxRatchetRamp is the maximum velocity ramp of ratchets in percent.
+	MaxRatchetRamp int = 100
 )
 
 var lastUsedChannel uint8 = defaultChannel
@@ -39,14 +44,50 @@ type Note struct {
 	Key         *KeyValue
 	Channel     *common.ControlValue[uint8]
 	Velocity    *common.ControlValue[uint8]
-	Length      *common.ControlValue[uint8]
+	Release     *common.ControlValue[uint8] // Note off velocity, 0 sends a plain note off.
+	Length      *common.ControlValue[int]
+	Tie         bool // Hold the note until the next trigger.
+	Timing      *common.ControlValue[int]
+	Ratchet     *common.ControlValue[int]
+	RatchetRamp int // Velocity change of the last ratchet hit in percent.
+	Legato      bool
+	Glide       int // Glide time in pulses, 0 disables glide.
+	GlideMode   GlideMode
 	Probability uint8
 
 	Controls     []*CC
+	SysEx        *SysEx
 	MetaCommands []meta.Command
 
+	performance *Performance
+	mixer       *Mixer
+	pending     []pendingNote // Triggers delayed by timing offsets and ratchets.
+
+	portamento bool  // Portamento switch state sent to the synth.
+	bend       int16 // Current pitch bend of a glide slide.
+	bendPulse  int
+
 	pulse     uint64 // Internal pulse counter to manage note length.
 	triggered bool
+	early     earlyTrigger // Trigger already handled on the previous step.
+}
+
+// earlyTrigger is the outcome of a trigger handled a step ahead.
+type earlyTrigger uint8
+
+const (
+	noEarly earlyTrigger = iota
+	earlyPlayed
+	earlyDropped
+)
+
+// pendingNote is a note trigger waiting to be sent.
+type pendingNote struct {
+	pulses   int
+	root     theory.Key
+	scale    theory.Scale
+	velocity uint8
+	first    bool // First ratchet hit, sending the controls.
 }
 
 // NewNote initializes a new Note with default settings and the provided MIDI interface.
@@ -72,9 +113,13 @@ func NewNote(midi midi.Midi, device *midi.Device) *Note {
 		Key:          NewKeyValue(defaultKey),
 		Channel:      common.NewControlValue[uint8](lastUsedChannel, 0, maxChannel),
 		Velocity:     common.NewControlValue[uint8](defaultVelocity, 0, maxVelocity),
-		Length:       common.NewControlValue[uint8](defaultLength, minLength, maxLength),
+		Release:      common.NewControlValue[uint8](defaultRelease, 0, maxVelocity),
+		Length:       common.NewControlValue[int](defaultLength, minLength, MaxLength),
+		Timing:       common.NewControlValue[int](0, -maxTiming, maxTiming),
+		Ratchet:      common.NewControlValue[int](1, 1, MaxRatchet),
 		Probability:  maxProbability,
 		Controls:     ccs,
+		SysEx:        NewSysEx(midi),
 		MetaCommands: cmds,
 	}
 }
@@ -85,7 +130,10 @@ func (n Note) Copy() *Note {
 	newKey := *n.Key
 	newChannel := *n.Channel
 	newVelocity := *n.Velocity
+	newRelease := *n.Release
 	newLength := *n.Length
+	newTiming := *n.Timing
+	newRatchet := *n.Ratchet
 	source := rand.NewSource(time.Now().UnixNano())
 	newControls := make([]*CC, defaultCCNumbers)
 	for i, c := range n.Controls {
@@ -102,59 +150,208 @@ func (n Note) Copy() *Note {
 		Key:          &newKey,
 		Channel:      &newChannel,
 		Velocity:     &newVelocity,
+		Release:      &newRelease,
 		Length:       &newLength,
+		Tie:          n.Tie,
+		Timing:       &newTiming,
+		Ratchet:      &newRatchet,
+		RatchetRamp:  n.RatchetRamp,
+		Legato:       n.Legato,
+		Glide:        n.Glide,
+		GlideMode:    n.GlideMode,
 		Probability:  n.Probability,
 		Controls:     newControls,
+		SysEx:        n.SysEx.Copy(),
 		MetaCommands: newCmds,
+		performance:  n.performance,
+		mixer:        n.mixer,
 	}
 }
 
-// Tick advances the internal pulse counter and stops the note if it exceeds its length.
+// Tick advances the internal pulse counter, stops the note if it exceeds its
+// length and sends pending notes that are due.
 func (n *Note) Tick() {
+	defer n.tickPending()
+	n.tickGlide()
 	if !n.triggered {
 		return
 	}
 	n.pulse++
 
+	for _, control := range n.Controls {
+		control.Modulate(n.Device.Get(), n.Channel.Last(), uint8(n.Key.Last()), int(n.pulse), n.Length.Last())
+	}
+
+	// Tied notes are held until the next trigger.
+	if n.Tie {
+		return
+	}
+
 	// Stop the note if its duration is complete.
-	if n.Length.Last() < maxLength && n.pulse >= uint64(n.Length.Last()) {
-		n.Stop()
+	if n.pulse >= uint64(n.Length.Last()) {
+		n.release()
 	}
 }
 
-// TransposeAndPlay triggers the note with a specific root and scale, resetting internal state.
+// TransposeAndPlay triggers the note with a specific root and scale. The note
+// is sent right away or delayed according to its timing offset.
 func (n *Note) TransposeAndPlay(root theory.Key, scale theory.Scale) {
-	if n.Key.IsSilent() {
+	early := n.early
+	n.early = noEarly
+	if early == earlyDropped || (early == noEarly && !n.mustPlay()) {
 		return
 	}
 
-	if n.Probability < maxProbability &&
-		uint8(rand.Int31n((100))) >= n.Probability {
+	for _, cmd := range n.MetaCommands {
+		cmd.Execute()
+	}
+	if early == earlyPlayed {
 		return
 	}
+	n.schedule(root, scale, 0)
+}
 
-	n.Transpose(root, scale)
-	n.Stop()
-	n.midi.NoteOn(
-		n.Device.Get(),
-		n.Channel.Computed(),
-		uint8(n.Key.Computed(root, scale)),
-		n.Velocity.Computed(),
-	)
-	n.Length.Computed() // Just trigger length computation
+// PlayEarly handles the trigger of the next step right away, so that a
+// negative timing offset sends the note ahead of the step. The next
+// trigger is then skipped, only executing the meta commands.
+func (n *Note) PlayEarly(root theory.Key, scale theory.Scale) {
+	if n.early != noEarly {
+		return
+	}
+	n.early = earlyDropped
+	if !n.mustPlay() {
+		return
+	}
+	n.early = earlyPlayed
+	n.schedule(root, scale, common.PulsesPerStep)
+}
 
-	for _, control := range n.Controls {
-		control.Send(n.Device.Get(), n.Channel.Last())
+// ClearEarly forgets an early trigger whose step did not trigger the note.
+func (n *Note) ClearEarly() {
+	n.early = noEarly
+}
+
+// Early returns true if the note timing can send it ahead of its step,
+// before the grid latency.
+func (n *Note) Early() bool {
+	timing := min(n.Timing.Value(), n.Timing.Value()+n.Timing.RandomAmount())
+	if n.performance != nil {
+		timing += n.performance.Latency
 	}
+	return timing < 0
+}
 
-	for _, cmd := range n.MetaCommands {
-		cmd.Execute()
+// mustPlay returns true if a trigger plays the note, according to its
+// key and probability.
+func (n *Note) mustPlay() bool {
+	if n.Key.IsSilent() {
+		return false
+	}
+	return n.Probability >= maxProbability ||
+		uint8(rand.Int31n((100))) < n.Probability
+}
+
+// schedule sends the note hits after the given wait in pulses, shifted
+// by the note timing offset.
+func (n *Note) schedule(root theory.Key, scale theory.Scale, wait int) {
+	delay := n.performance.delay(wait+n.Timing.Computed(), n.rand)
+	velocity := n.Velocity.Computed()
+	// Ratchet hits are spread over the step, each on its own pulse: more
+	// hits than step pulses run into the next step.
+	hits := n.Ratchet.Computed()
+	span := max(common.PulsesPerStep, hits)
+	for i := 0; i < hits; i++ {
+		pulses := delay + i*span/hits
+		hitVelocity := ratchetVelocity(velocity, n.RatchetRamp, i, hits)
+		if pulses == 0 {
+			n.play(root, scale, hitVelocity, i == 0)
+			continue
+		}
+		n.pending = append(n.pending, pendingNote{
+			pulses:   pulses,
+			root:     root,
+			scale:    scale,
+			velocity: hitVelocity,
+			first:    i == 0,
+		})
+	}
+}
+
+// ratchetVelocity returns the velocity of a ratchet hit, linearly ramped
+// from the note velocity to the last hit.
+func ratchetVelocity(velocity uint8, ramp, hit, hits int) uint8 {
+	if ramp == 0 || hits < 2 || velocity == 0 {
+		return velocity
+	}
+	v := int(velocity) * (100*(hits-1) + ramp*hit) / (100 * (hits - 1))
+	return uint8(max(min(v, int(maxVelocity)), 1))
+}
+
+// play transposes and sends the note, resetting internal state. In legato
+// mode, the previous note is released after the new one is sent. Controls
+// are only sent with the first hit of a ratchet.
+func (n *Note) play(root theory.Key, scale theory.Scale, velocity uint8, controls bool) {
+	previousKey, previousChannel := uint8(n.Key.Last()), n.Channel.Last()
+	sounding := n.triggered
+	legato := n.Legato && sounding
+
+	n.Transpose(root, scale)
+	if !legato {
+		n.release()
+	}
+	channel := n.Channel.Computed()
+	key := uint8(n.Key.Computed(root, scale))
+	if !n.mixer.Audible(n, n.Device.Get(), channel) {
+		if legato {
+			n.noteOff(previousChannel, previousKey)
+		}
+		n.triggered = false
+		return
+	}
+	n.glide(n.Device.Get(), channel, previousKey, key, sounding)
+
+	// A tied legato note on the same key is just held.
+	if !legato || key != previousKey || channel != previousChannel {
+		n.midi.NoteOn(
+			n.Device.Get(),
+			channel,
+			key,
+			n.performance.velocity(velocity, n.rand),
+		)
+	}
+	if legato && (key != previousKey || channel != previousChannel) {
+		n.noteOff(previousChannel, previousKey)
+	}
+	n.Length.Computed() // Just trigger length computation
+
+	if controls {
+		for _, control := range n.Controls {
+			control.Send(n.Device.Get(), n.Channel.Last(), key)
+		}
+		n.SysEx.Send(n.Device.Get())
 	}
 
 	n.triggered = true
 	n.pulse = 0
 }
 
+// tickPending counts down pending notes and sends the ones that are due.
+func (n *Note) tickPending() {
+	if len(n.pending) == 0 {
+		return
+	}
+	remaining := n.pending[:0]
+	for _, p := range n.pending {
+		p.pulses--
+		if p.pulses > 0 {
+			remaining = append(remaining, p)
+			continue
+		}
+		n.play(p.root, p.scale, p.velocity, p.first)
+	}
+	n.pending = remaining
+}
+
 // Play just triggers the note. Used for note preview.
 func (n *Note) Play() {
 	if n.Key.IsSilent() {
@@ -176,17 +373,53 @@ func (n *Note) Play() {
 // Silence silences the note channel
 func (n *Note) Silence() {
 	n.midi.Silence(n.Device.Get(), n.Channel.Value())
+	n.pending = nil
+	n.early = noEarly
 	n.triggered = false
 	n.pulse = 0
 }
 
-// Stop sends a MIDI Note Off message and resets the triggered state.
+// Stop sends a MIDI Note Off message, drops pending notes and resets the
+// triggered state.
 func (n *Note) Stop() {
-	n.midi.NoteOff(n.Device.Get(), n.Channel.Last(), uint8(n.Key.Last()))
+	n.pending = nil
+	n.early = noEarly
+	n.stopGlide()
+	n.release()
+}
+
+// release sends a MIDI Note Off message and resets the triggered state.
+func (n *Note) release() {
+	n.noteOff(n.Channel.Last(), uint8(n.Key.Last()))
 	n.triggered = false
 	n.pulse = 0
 }
 
+// noteOff sends a MIDI Note Off message, with the release velocity if set.
+func (n *Note) noteOff(channel, key uint8) {
+	if n.Release.Value() == 0 && n.Release.RandomAmount() == 0 {
+		n.midi.NoteOff(n.Device.Get(), channel, key)
+		return
+	}
+	n.midi.NoteOffVelocity(n.Device.Get(), channel, key, n.Release.Computed())
+}
+
+// SetMidi sets the midi interface the note is sent to.
+func (n *Note) SetMidi(midi midi.Midi) {
+	n.midi = midi
+}
+
+// SetPerformance attaches the grid-wide timing settings to the note.
+func (n *Note) SetPerformance(performance *Performance) {
+	n.performance = performance
+}
+
+// SetMixer attaches the mixer shared by the grid and its layers to the
+// note.
+func (n *Note) SetMixer(mixer *Mixer) {
+	n.mixer = mixer
+}
+
 // Transpose transposes current key for a given root and scale.
 func (n *Note) Transpose(root theory.Key, scale theory.Scale) {
 	n.Key.SetNext(n.Key.key.Transpose(root, scale, n.Key.interval), root)
@@ -206,7 +439,7 @@ func (n *Note) SetVelocity(velocity uint8) {
 }
 
 // SetLength updates the length of the note.
-func (n *Note) SetLength(length uint8) {
+func (n *Note) SetLength(length int) {
 	n.Length.Set(length)
 }
 
@@ -237,6 +470,9 @@ func (n *Note) Symbol() string {
 			break
 		}
 	}
+	if controlsTypeNb == 0 && n.SysEx.Active() {
+		controlsTypeNb += 1
+	}
 	for _, c := range n.MetaCommands {
 		if c.Active() {
 			controlsTypeNb += 1
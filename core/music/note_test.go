@@ -0,0 +1,224 @@
+package music
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"signls/core/common"
+	"signls/core/theory"
+	"signls/midi"
+)
+
+func TestNoteTiming(t *testing.T) {
+	tests := []struct {
+		latency int
+		timing  int
+		early   bool
+		delay   int
+	}{
+		{0, 0, false, 0},
+		{0, 2, false, 2},
+		{0, -2, false, 0},
+		{3, -2, false, 1},
+		{3, 4, false, 7},
+		{0, -2, true, 4},
+		{1, -5, true, 2},
+	}
+	for _, tt := range tests {
+		note := NewNote(&midi.Mock{}, &midi.Device{})
+		note.SetPerformance(&Performance{Latency: tt.latency})
+		note.Timing.Set(tt.timing)
+
+		if tt.early {
+			note.PlayEarly(60, theory.CHROMATIC)
+		} else {
+			note.TransposeAndPlay(60, theory.CHROMATIC)
+		}
+		for i := 0; i < tt.delay; i++ {
+			if note.triggered {
+				t.Fatalf("latency %d, timing %d: note sent after %d pulses, want %d", tt.latency, tt.timing, i, tt.delay)
+			}
+			note.Tick()
+		}
+		if !note.triggered {
+			t.Fatalf("latency %d, timing %d: note not sent after %d pulses", tt.latency, tt.timing, tt.delay)
+		}
+	}
+}
+
+func TestRatchetVelocity(t *testing.T) {
+	tests := []struct {
+		velocity uint8
+		ramp     int
+		hit      int
+		hits     int
+		want     uint8
+	}{
+		{100, 0, 3, 4, 100},
+		{100, 50, 0, 3, 100},
+		{100, 50, 1, 3, 125},
+		{100, 50, 2, 3, 127},
+		{100, -50, 2, 3, 50},
+		{100, -100, 1, 2, 1},
+		{0, 50, 1, 2, 0},
+	}
+	for _, tt := range tests {
+		if got := ratchetVelocity(tt.velocity, tt.ramp, tt.hit, tt.hits); got != tt.want {
+			t.Fatalf("velocity %d, ramp %d, hit %d/%d: got %d, want %d", tt.velocity, tt.ramp, tt.hit, tt.hits, got, tt.want)
+		}
+	}
+}
+
+// recorder is a midi mock recording the sent messages.
+type recorder struct {
+	midi.Mock
+	messages []string
+}
+
+func (r *recorder) record(format string, args ...any) {
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) NoteOn(device int, channel uint8, note uint8, velocity uint8) {
+	r.record("on %d %d", note, velocity)
+}
+
+func (r *recorder) NoteOff(device int, channel uint8, note uint8) {
+	r.record("off %d", note)
+}
+
+func (r *recorder) NoteOffVelocity(device int, channel uint8, note uint8, velocity uint8) {
+	r.record("off %d %d", note, velocity)
+}
+
+func (r *recorder) ControlChange(device int, channel, controller, value uint8) {
+	r.record("cc %d %d", controller, value)
+}
+
+func (r *recorder) Pitchbend(device int, channel uint8, value int16) {
+	r.record("bend %d", value)
+}
+
+func (r *recorder) PolyAfterTouch(device int, channel uint8, note uint8, value uint8) {
+	r.record("poly %d %d", note, value)
+}
+
+func (r *recorder) SysEx(device int, data []byte) {
+	r.record("sysex % X", data)
+}
+
+// take returns and clears the recorded messages.
+func (r *recorder) take() []string {
+	messages := r.messages
+	r.messages = nil
+	return messages
+}
+
+func TestRatchet(t *testing.T) {
+	// Hits are at most one pulse apart, so more hits than step pulses
+	// run into the next step. Latency delays them all.
+	for _, latency := range []int{0, 3} {
+		for _, hits := range []int{3, 6, 7, 8} {
+			rec := &recorder{}
+			note := NewNote(rec, &midi.Device{})
+			note.SetPerformance(&Performance{Latency: latency})
+			note.Ratchet.Set(hits)
+			note.Controls[0].SetType(int(ControlChangeControlType))
+			note.Controls[0].SetController(7)
+			note.Controls[0].Value.Set(50)
+
+			note.TransposeAndPlay(60, theory.CHROMATIC)
+			sent := []int{}
+			for pulse := 0; pulse <= latency+2*common.PulsesPerStep; pulse++ {
+				for _, m := range rec.take() {
+					switch m {
+					case "on 60 100":
+						sent = append(sent, pulse)
+					case "cc 7 50":
+						if pulse != latency {
+							t.Fatalf("latency %d, %d hits: control sent on pulse %d", latency, hits, pulse)
+						}
+					}
+				}
+				note.Tick()
+			}
+			want := []int{}
+			for i := 0; i < hits; i++ {
+				want = append(want, latency+i*max(common.PulsesPerStep, hits)/hits)
+			}
+			if !reflect.DeepEqual(sent, want) {
+				t.Fatalf("latency %d, %d hits: notes sent on pulses %v, want %v", latency, hits, sent, want)
+			}
+		}
+	}
+}
+
+func TestPolyAfterTouch(t *testing.T) {
+	rec := &recorder{}
+	note := NewNote(rec, &midi.Device{})
+	note.SetKey(62, 60)
+	note.Controls[0].SetType(int(PolyAfterTouchControlType))
+	note.Controls[0].Value.Set(90)
+	note.Controls[0].Shape = RampControlShape
+	note.Controls[0].End.Set(30)
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	rec.take()
+
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	note.Tick()
+	// The pressure applies to the note key, and follows its shape.
+	want := []string{"off 62", "on 62 100", "poly 62 90", "poly 62 80"}
+	if got := rec.take(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNoteTie(t *testing.T) {
+	rec := &recorder{}
+	note := NewNote(rec, &midi.Device{})
+	note.SetKey(60, 60)
+	note.Tie = true
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	rec.take()
+
+	// A tied note is held past its length, until the next trigger.
+	for i := 0; i < 2*note.Length.Last(); i++ {
+		note.Tick()
+	}
+	if got := rec.take(); len(got) != 0 {
+		t.Fatalf("tied note released before the next trigger: %v", got)
+	}
+	note.TransposeAndPlay(60, theory.CHROMATIC)
+	if got, want := rec.take(), []string{"off 60", "on 60 100"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	note.Stop()
+	if got, want := rec.take(), []string{"off 60"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNoteRelease(t *testing.T) {
+	for _, tt := range []struct {
+		release uint8
+		want    string
+	}{
+		{0, "off 60"},
+		{64, "off 60 64"},
+	} {
+		rec := &recorder{}
+		note := NewNote(rec, &midi.Device{})
+		note.SetKey(60, 60)
+		note.Release.Set(tt.release)
+		note.TransposeAndPlay(60, theory.CHROMATIC)
+		rec.take()
+
+		for i := 0; i < note.Length.Last(); i++ {
+			note.Tick()
+		}
+		if got := rec.take(); !reflect.DeepEqual(got, []string{tt.want}) {
+			t.Fatalf("release %d: got %v, want %s", tt.release, got, tt.want)
+		}
+	}
+}
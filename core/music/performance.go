@@ -0,0 +1,66 @@
+package music
+
+import (
+	"math/rand"
+
+	"signls/core/common"
+)
+
+const (
+	// MaxLatency is the maximum grid latency in pulses.
+	MaxLatency = common.PulsesPerStep - 1
+	// MaxHumanize is the maximum humanize depth in percent.
+	MaxHumanize = 100
+
+	maxTiming           = common.PulsesPerStep - 1
+	maxHumanizeTiming   = 2  // Timing deviation in pulses at full depth.
+	maxHumanizeVelocity = 24 // Velocity deviation at full depth.
+)
+
+// Performance holds the grid-wide timing settings shared by all notes.
+// Latency delays every note by a number of pulses, leaving room for
+// negative timing offsets. Humanize randomly shifts note timing and
+// velocity.
+type Performance struct {
+	Latency   int
+	Humanize  int
+	BendRange int // Synth pitch bend range in semitones, used by glides.
+}
+
+// bendRange returns the pitch bend range, falling back to the usual
+// synth default.
+func (p *Performance) bendRange() int {
+	if p == nil || p.BendRange == 0 {
+		return DefaultBendRange
+	}
+	return p.BendRange
+}
+
+// delay returns the number of pulses a note with the given timing
+// offset should wait before being sent.
+func (p *Performance) delay(timing int, r *rand.Rand) int {
+	delay := timing
+	if p != nil {
+		delay += p.Latency + p.deviation(maxHumanizeTiming, r)
+	}
+	return max(delay, 0)
+}
+
+// velocity returns the humanized velocity.
+func (p *Performance) velocity(velocity uint8, r *rand.Rand) uint8 {
+	if p == nil || velocity == 0 {
+		return velocity
+	}
+	v := int(velocity) + p.deviation(maxHumanizeVelocity, r)
+	return uint8(max(min(v, int(maxVelocity)), 1))
+}
+
+// deviation returns a random value in [-depth, depth] scaled by the
+// humanize amount.
+func (p *Performance) deviation(depth int, r *rand.Rand) int {
+	amount := depth * p.Humanize / MaxHumanize
+	if amount == 0 {
+		return 0
+	}
+	return r.Intn(2*amount+1) - amount
+}
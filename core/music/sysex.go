@@ -0,0 +1,132 @@
+package music
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"signls/core/common"
+	"signls/midi"
+)
+
+const (
+	// sysExValuePlaceholder is replaced by the computed value when the
+	// message is sent.
+	sysExValuePlaceholder = "VV"
+
+	sysExStart byte = 0xF0
+	sysExEnd   byte = 0xF7
+)
+
+// SysEx is a raw system exclusive message sent when a note triggers.
+type SysEx struct {
+	midi midi.Midi
+
+	Payload string
+	Value   *common.ControlValue[int]
+
+	data   []byte
+	values []int // Indexes of the value placeholders in data.
+}
+
+// NewSysEx creates an empty SysEx message.
+func NewSysEx(midi midi.Midi) *SysEx {
+	return &SysEx{
+		midi:  midi,
+		Value: common.NewControlValue[int](defaultControlValue, minControlValue, maxControlValue),
+	}
+}
+
+// Copy creates a copy of the SysEx message.
+func (s SysEx) Copy() *SysEx {
+	newValue := *s.Value
+	return &SysEx{
+		midi:    s.midi,
+		Payload: s.Payload,
+		Value:   &newValue,
+		data:    s.data,
+		values:  s.values,
+	}
+}
+
+// Active returns true if a payload is set.
+func (s SysEx) Active() bool {
+	return len(s.data) > 0
+}
+
+// HasValue returns true if the payload contains a value placeholder.
+func (s SysEx) HasValue() bool {
+	return len(s.values) > 0
+}
+
+// SetPayload parses and sets a hex payload (ex F0 41 10 42 12 40 00 7F vv F7).
+// The start and end bytes are optional and vv is replaced by the value.
+// An empty payload disables the message.
+func (s *SysEx) SetPayload(payload string) error {
+	data, values, err := parseSysEx(payload)
+	if err != nil {
+		return err
+	}
+	s.Payload = strings.ToUpper(strings.Join(strings.Fields(payload), " "))
+	s.data = data
+	s.values = values
+	return nil
+}
+
+// Send sends the SysEx message with the value substituted.
+func (s SysEx) Send(device int) {
+	if !s.Active() {
+		return
+	}
+	data := s.data
+	if s.HasValue() {
+		data = make([]byte, len(s.data))
+		copy(data, s.data)
+		value := s.Value.Computed()
+		for _, i := range s.values {
+			data[i] = byte(value)
+		}
+	}
+	s.midi.SysEx(device, data)
+}
+
+// parseSysEx converts a hex payload to the inner SysEx bytes, returning the
+// positions of the value placeholders.
+func parseSysEx(payload string) ([]byte, []int, error) {
+	payload = strings.ToUpper(strings.Join(strings.Fields(payload), ""))
+	if len(payload)%2 != 0 {
+		return nil, nil, errors.New("sysex payload must contain whole bytes")
+	}
+
+	data := []byte{}
+	values := []int{}
+	for i := 0; i < len(payload); i += 2 {
+		token := payload[i : i+2]
+		if token == sysExValuePlaceholder {
+			values = append(values, len(data))
+			data = append(data, 0)
+			continue
+		}
+		b, err := hex.DecodeString(token)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, b[0])
+	}
+
+	if len(data) > 0 && data[0] == sysExStart {
+		data = data[1:]
+		for i := range values {
+			values[i]--
+		}
+	}
+	if len(data) > 0 && data[len(data)-1] == sysExEnd {
+		data = data[:len(data)-1]
+	}
+	for _, b := range data {
+		if b > 0x7F {
+			return nil, nil, errors.New("sysex data bytes must be lower than 80")
+		}
+	}
+	return data, values, nil
+}
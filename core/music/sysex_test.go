@@ -0,0 +1,42 @@
+package music
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseSysEx(t *testing.T) {
+	tests := []struct {
+		payload string
+		data    []byte
+		values  []int
+	}{
+		{"", []byte{}, []int{}},
+		{"F0 41 10 42 F7", []byte{0x41, 0x10, 0x42}, []int{}},
+		{"41 10 vv 12", []byte{0x41, 0x10, 0x00, 0x12}, []int{2}},
+		{"f0 43 VV vv f7", []byte{0x43, 0x00, 0x00}, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		data, values, err := parseSysEx(tt.payload)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.payload, err)
+		}
+		if !bytes.Equal(data, tt.data) {
+			t.Fatalf("%s: got data %X, want %X", tt.payload, data, tt.data)
+		}
+		if len(values) != len(tt.values) {
+			t.Fatalf("%s: got values %v, want %v", tt.payload, values, tt.values)
+		}
+		for i := range values {
+			if values[i] != tt.values[i] {
+				t.Fatalf("%s: got values %v, want %v", tt.payload, values, tt.values)
+			}
+		}
+	}
+
+	for _, payload := range []string{"F0 4", "F0 ZZ F7", "41 90 00"} {
+		if _, _, err := parseSysEx(payload); err == nil {
+			t.Fatalf("%s: expected an error", payload)
+		}
+	}
+}
@@ -40,8 +40,4 @@ func (e *PassEmitter) Name() string {
 	return "pass"
 }
 
-func (e *PassEmitter) Color() string {
-	return "35"
-}
-
 func (e *PassEmitter) Reset() {}
@@ -0,0 +1,93 @@
+package theory
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseScala reads a Scala (.scl) tuning file and maps its pitches onto
+// the closest semitones of a 12-step scale. It returns the scale
+// description found in the file along with the scale.
+//
+// Read more: https://www.huygens-fokker.org/scala/scl_format.html
+func ParseScala(r io.Reader) (string, Scale, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", 0, err
+	}
+
+	if len(lines) < 2 {
+		return "", 0, errors.New("scala file is missing a description or a note count")
+	}
+	description := lines[0]
+	count, err := strconv.Atoi(firstField(lines[1]))
+	if err != nil || count < 0 {
+		return "", 0, fmt.Errorf("invalid scala note count: %s", lines[1])
+	}
+
+	pitches := []string{}
+	for _, line := range lines[2:] {
+		if line == "" {
+			continue
+		}
+		pitches = append(pitches, firstField(line))
+	}
+	if len(pitches) != count {
+		return "", 0, fmt.Errorf("scala file declares %d notes, found %d", count, len(pitches))
+	}
+
+	scale := Scale(UNISON)
+	for _, p := range pitches {
+		cents, err := parseScalaPitch(p)
+		if err != nil {
+			return "", 0, err
+		}
+		scale |= 1 << mod(int(math.Round(cents/100)), 12)
+	}
+
+	return description, scale, nil
+}
+
+// parseScalaPitch converts a scala pitch, either expressed in cents
+// (ex: 701.955) or as a ratio (ex: 3/2 or 2), to cents.
+func parseScalaPitch(pitch string) (float64, error) {
+	if strings.Contains(pitch, ".") {
+		cents, err := strconv.ParseFloat(pitch, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid scala pitch: %s", pitch)
+		}
+		return cents, nil
+	}
+
+	numerator, denominator, found := strings.Cut(pitch, "/")
+	if !found {
+		denominator = "1"
+	}
+	n, errN := strconv.ParseFloat(numerator, 64)
+	d, errD := strconv.ParseFloat(denominator, 64)
+	if errN != nil || errD != nil || n <= 0 || d <= 0 {
+		return 0, fmt.Errorf("invalid scala pitch: %s", pitch)
+	}
+	return 1200 * math.Log2(n/d), nil
+}
+
+func firstField(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,76 @@
+package theory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseScala(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		description string
+		want        Scale
+	}{
+		{
+			name: "just major",
+			file: `! just.scl
+!
+Just intonation major
+ 7
+!
+ 9/8
+ 5/4
+ 4/3
+ 3/2
+ 5/3
+ 15/8
+ 2/1
+`,
+			description: "Just intonation major",
+			want:        IONIAN,
+		},
+		{
+			name: "cents",
+			file: `! hijaz.scl
+Hijaz approximation
+7
+113.685 minor second
+386.314
+498.045
+701.955
+813.686
+996.090
+1200.0
+`,
+			description: "Hijaz approximation",
+			want:        Scale(UNISON | MINOR_2ND | MAJOR_3RD | FOURTH | FIFTH | MINOR_6TH | MINOR_7TH),
+		},
+	}
+	for _, tt := range tests {
+		description, scale, err := ParseScala(strings.NewReader(tt.file))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+		if description != tt.description {
+			t.Fatalf("%s: got description %q, want %q", tt.name, description, tt.description)
+		}
+		if scale != tt.want {
+			t.Fatalf("%s: got intervals %v, want %v", tt.name, scale.Intervals(), tt.want.Intervals())
+		}
+	}
+}
+
+func TestParseScalaErrors(t *testing.T) {
+	files := []string{
+		"description only\n",
+		"bad count\nfoo\n",
+		"missing notes\n3\n100.0\n",
+		"bad pitch\n1\nfoo\n",
+	}
+	for _, f := range files {
+		if _, _, err := ParseScala(strings.NewReader(f)); err == nil {
+			t.Fatalf("expected an error parsing %q", f)
+		}
+	}
+}
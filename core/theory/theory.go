@@ -1,6 +1,7 @@
 package theory
 
 import (
+	"errors"
 	"math"
 
 	"signls/midi"
@@ -178,6 +179,32 @@ func AllScales() []Scale {
 	return allScales
 }
 
+// NewScale builds a scale from a list of semitone intervals relative to the
+// root (ex: 0, 2, 3, 5, 7, 8, 11 for harmonic minor). The unison is always
+// part of the scale.
+func NewScale(intervals []int) (Scale, error) {
+	scale := Scale(UNISON)
+	for _, i := range intervals {
+		if i < 0 || i > 11 {
+			return 0, errors.New("scale intervals must be between 0 and 11")
+		}
+		scale |= 1 << i
+	}
+	return scale, nil
+}
+
+// RegisterScale adds a user-defined scale to the list of available scales.
+// It returns false if the scale is already known, under any name.
+func RegisterScale(name string, scale Scale) bool {
+	scale = (scale | Scale(UNISON)) & CHROMATIC
+	if _, ok := scaleNames[scale]; ok {
+		return false
+	}
+	allScales = append(allScales, scale)
+	scaleNames[scale] = name
+	return true
+}
+
 // AllKeysInScale returns all MIDI keys within the given scale, relative to the root key.
 func AllKeysInScale(root Key, scale Scale) []Key {
 	var keys []Key
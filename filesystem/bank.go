@@ -3,14 +3,18 @@
 package filesystem
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"signls/core/common"
 	"signls/core/music"
@@ -30,13 +34,28 @@ const (
 type Bank struct {
 	mu sync.Mutex
 
-	Grids    []Grid `json:"grids"`
-	Active   int    `json:"active"`
-	filename string
+	FormatVersion int    `json:"version"`
+	Grids         []Grid `json:"grids"`
+	Active        int    `json:"active"`
+	Song          Song   `json:"song"`
+	Quantize      int    `json:"quantize"` // Grid switching quantization in steps.
+	filename      string
+
+	err      error  // Last read or write error.
+	readOnly bool   // Set when the file cannot be read, to keep it intact.
+	recovery string // Newest backup that can be read when the file cannot.
+
+	modTime time.Time    // File modification time when last read or written.
+	size    int64        // File size when last read or written.
+	pending map[int]Grid // Grids changed on disk, waiting for a reload.
 }
 
 // Grid holds a grid in memory
 type Grid struct {
+	Name  string `json:"name"`
+	Color int    `json:"color"` // Color tag, 0 being untagged.
+	Notes string `json:"notes"`
+
 	Nodes []Node  `json:"nodes"`
 	Tempo float64 `json:"tempo"`
 
@@ -48,6 +67,12 @@ type Grid struct {
 	Key   uint8  `json:"key"`
 	Scale uint16 `json:"scale"`
 
+	Progression []Chord `json:"progression"`
+
+	Latency   int `json:"latency"`
+	Humanize  int `json:"humanize"`
+	BendRange int `json:"bend_range"`
+
 	SendClock     bool `json:"send_clock"`
 	SendTransport bool `json:"send_transport"`
 }
@@ -69,6 +94,52 @@ func (g Grid) IsEmpty() bool {
 	return len(g.Nodes) == 0
 }
 
+// Chord represents a chord progression step that is json serializable.
+type Chord struct {
+	Key   uint8  `json:"key"`
+	Scale uint16 `json:"scale"`
+	Bars  int    `json:"bars"`
+}
+
+// NodeType is a grid node type: its name in bank files, queries and
+// commands, and the symbol adding it, upper cased as its text format letter.
+type NodeType struct {
+	Name   string
+	Symbol string
+}
+
+// Letter returns the letter of the node type in the text format.
+func (t NodeType) Letter() rune {
+	return unicode.ToUpper([]rune(t.Symbol)[0])
+}
+
+var nodeTypes = []NodeType{
+	{Name: "bang", Symbol: "b"},
+	{Name: "euclid", Symbol: "e"},
+	{Name: "pass", Symbol: "p"},
+	{Name: "spread", Symbol: "s"},
+	{Name: "cycle", Symbol: "c"},
+	{Name: "dice", Symbol: "d"},
+	{Name: "toll", Symbol: "t"},
+	{Name: "zone", Symbol: "z"},
+	{Name: "hole", Symbol: "h"},
+}
+
+// NodeTypes returns all the grid node types.
+func NodeTypes() []NodeType {
+	return append([]NodeType(nil), nodeTypes...)
+}
+
+// FindNodeType returns the node type with the given name.
+func FindNodeType(name string) (NodeType, bool) {
+	for _, t := range nodeTypes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return NodeType{}, false
+}
+
 // Node represents a grid node that is json serializable.
 type Node struct {
 	X         int    `json:"x"`
@@ -86,9 +157,18 @@ type Note struct {
 	Key          Key                    `json:"key"`
 	Channel      Param                  `json:"channel"`
 	Velocity     Param                  `json:"velocity"`
+	Release      Param                  `json:"release"`
 	Length       Param                  `json:"length"`
+	Tie          bool                   `json:"tie"`
+	Timing       Param                  `json:"timing"`
+	Ratchet      Param                  `json:"ratchet"`
+	RatchetRamp  int                    `json:"ratchet_ramp"`
+	Legato       bool                   `json:"legato"`
+	Glide        int                    `json:"glide"`
+	GlideMode    int                    `json:"glide_mode"`
 	Probability  int                    `json:"probability"`
 	Controls     []CC                   `json:"controls"`
+	SysEx        SysEx                  `json:"sysex"`
 	MetaCommands map[string]MetaCommand `json:"meta_commands"`
 }
 
@@ -105,9 +185,18 @@ func NewNote(n music.Note) Note {
 		Key:          NewKey(*n.Key),
 		Channel:      NewParam(*n.Channel),
 		Velocity:     NewParam(*n.Velocity),
+		Release:      NewParam(*n.Release),
 		Length:       NewParam(*n.Length),
+		Tie:          n.Tie,
+		Timing:       NewParam(*n.Timing),
+		Ratchet:      NewParam(*n.Ratchet),
+		RatchetRamp:  n.RatchetRamp,
+		Legato:       n.Legato,
+		Glide:        n.Glide,
+		GlideMode:    int(n.GlideMode),
 		Probability:  int(n.Probability),
 		Controls:     controls,
+		SysEx:        NewSysEx(*n.SysEx),
 		MetaCommands: metaCmds,
 	}
 }
@@ -130,6 +219,9 @@ type CC struct {
 	Type       int   `json:"type"`
 	Controller int   `json:"controller"`
 	Value      Param `json:"value"`
+	Shape      int   `json:"shape"`
+	End        Param `json:"end"`
+	Attack     int   `json:"attack"`
 }
 
 func NewCC(cc music.CC) CC {
@@ -137,6 +229,21 @@ func NewCC(cc music.CC) CC {
 		Type:       int(cc.Type),
 		Controller: int(cc.Controller),
 		Value:      NewParam(*cc.Value),
+		Shape:      int(cc.Shape),
+		End:        NewParam(*cc.End),
+		Attack:     cc.Attack,
+	}
+}
+
+type SysEx struct {
+	Payload string `json:"payload"`
+	Value   Param  `json:"value"`
+}
+
+func NewSysEx(sysex music.SysEx) SysEx {
+	return SysEx{
+		Payload: sysex.Payload,
+		Value:   NewParam(*sysex.Value),
 	}
 }
 
@@ -164,18 +271,202 @@ func NewParam[T uint8 | int](p common.ControlValue[T]) Param {
 	}
 }
 
-// New creates and loads a new bank from a given file.
+// New creates and loads a new bank from a given file. When the file cannot
+// be read, the newest readable backup is kept for recovery.
 func New(filename string) *Bank {
+	bank := newBank(filename)
+	if err := bank.Read(filename); err != nil {
+		bank.err = fmt.Errorf("cannot read bank %s, changes won't be saved: %w", filename, err)
+		bank.readOnly = true
+		bank.recovery = findRecovery(filename)
+		return bank
+	}
+	bank.stat()
+	return bank
+}
+
+func newBank(filename string) *Bank {
 	grids := make([]Grid, maxGrids)
 	for k := range grids {
 		grids[k] = NewGrid()
 	}
-	bank := &Bank{
-		filename: filename,
-		Grids:    grids,
+	return &Bank{
+		FormatVersion: BankVersion,
+		filename:      filename,
+		Grids:         grids,
+		Song:          NewSong(),
 	}
-	bank.Read(filename)
-	return bank
+}
+
+// findRecovery returns the newest backup of a bank file that can be read,
+// or an empty string.
+func findRecovery(filename string) string {
+	for i := 1; i <= maxBackups; i++ {
+		backup := backupFilename(filename, i)
+		if _, err := os.Stat(backup); err != nil {
+			continue
+		}
+		if err := newBank(filename).read(backup, false); err == nil {
+			return backup
+		}
+	}
+	return ""
+}
+
+// Recovery returns the backup the bank can be recovered from when its file
+// cannot be read, or an empty string.
+func (b *Bank) Recovery() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.recovery
+}
+
+// Recover replaces the bank with its newest readable backup. The unreadable
+// file is kept aside with a .corrupt extension.
+func (b *Bank) Recover() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.recovery == "" {
+		return errors.New("no backup to recover from")
+	}
+
+	recovered := newBank(b.filename)
+	if err := recovered.read(b.recovery, false); err != nil {
+		return err
+	}
+	if err := copyFile(b.filename, b.filename+".corrupt"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot keep unreadable bank: %w", err)
+	}
+
+	b.FormatVersion = recovered.FormatVersion
+	b.Grids = recovered.Grids
+	b.Active = recovered.Active
+	b.Song = recovered.Song
+	b.Quantize = recovered.Quantize
+	b.readOnly = false
+	b.recovery = ""
+	b.stat()
+	b.Write()
+	return b.err
+}
+
+// stat keeps the file modification time and size, to detect external
+// changes.
+func (b *Bank) stat() {
+	info, err := os.Stat(b.filename)
+	if err != nil {
+		return
+	}
+	b.modTime = info.ModTime()
+	b.size = info.Size()
+}
+
+// changed returns true if the file was modified by another program since it
+// was last read or written.
+func (b *Bank) changed() bool {
+	if b.readOnly {
+		return false
+	}
+	info, err := os.Stat(b.filename)
+	if err != nil {
+		return false
+	}
+	return !info.ModTime().Equal(b.modTime) || info.Size() != b.size
+}
+
+// Changed returns true if the bank file was modified by another program
+// since it was last read or written.
+func (b *Bank) Changed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.changed()
+}
+
+// Reload reads the bank file again after an external change. Grids that
+// changed on disk are replaced, except the kept ones (the playing grids)
+// that wait for AcceptReload or RejectReload. It returns the kept grids
+// that changed.
+func (b *Bank) Reload(keep []int) ([]int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	disk := newBank(b.filename)
+	if err := disk.Read(b.filename); err != nil {
+		b.err = fmt.Errorf("cannot reload bank %s, changes won't be saved: %w", b.filename, err)
+		return nil, b.err
+	}
+	b.err = nil
+
+	b.pending = map[int]Grid{}
+	changed := []int{}
+	for i := range b.Grids {
+		if sameGrid(b.Grids[i], disk.Grids[i]) {
+			continue
+		}
+		if slices.Contains(keep, i) {
+			b.pending[i] = disk.Grids[i]
+			changed = append(changed, i)
+			continue
+		}
+		b.Grids[i] = disk.Grids[i]
+	}
+	b.Song.arrange(disk.Song)
+	b.Quantize = disk.Quantize
+	b.stat()
+	return changed, nil
+}
+
+// AcceptReload replaces the kept grids with their version on disk.
+func (b *Bank) AcceptReload() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, g := range b.pending {
+		b.Grids[i] = g
+	}
+	b.pending = nil
+}
+
+// RejectReload keeps the kept grids as they are in memory and writes them
+// over their version on disk.
+func (b *Bank) RejectReload() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending = nil
+	b.Write()
+}
+
+// sameGrid compares two grids by their serialized content.
+func sameGrid(a, b Grid) bool {
+	ca, errA := json.Marshal(a)
+	cb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ca, cb)
+}
+
+// Backup rotates the bank file backups, keeping a copy of the file as
+// currently written.
+func (b *Bank) Backup() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.readOnly {
+		return
+	}
+	if err := rotateBackups(b.filename); err != nil {
+		b.err = fmt.Errorf("cannot backup bank %s: %w", b.filename, err)
+	}
+}
+
+// Err returns the last error that occurred while reading or writing the
+// bank.
+func (b *Bank) Err() error {
+	return b.err
+}
+
+// AdvanceSong advances the song given the number of bars played by the
+// current grid. It returns the grid to load when the song moves on.
+func (b *Bank) AdvanceSong(bars int) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Song.Update(bars)
 }
 
 // ActiveGrid returns the active grid from the bank.
@@ -185,6 +476,13 @@ func (b *Bank) ActiveGrid() Grid {
 	return b.Grids[b.Active]
 }
 
+// Grid returns a grid from the bank.
+func (b *Bank) Grid(nb int) Grid {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Grids[nb]
+}
+
 // ClearGrid clears a given grid.
 func (b *Bank) ClearGrid(nb int) {
 	b.Grids[nb] = NewGrid()
@@ -195,40 +493,103 @@ func (b *Bank) Filename() string {
 	return strings.TrimSuffix(b.filename, filepath.Ext(b.filename))
 }
 
-// Save saves a grid to the active slot and writes.
-func (b *Bank) Save(grid Grid) {
+// Save saves a grid to the given slot and writes.
+func (b *Bank) Save(nb int, grid Grid) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.Grids[b.Active] = grid
+	b.Grids[nb] = grid
 	b.Write()
 }
 
-// Write serializes the Bank and writes it to a file.
+// Write serializes the Bank and atomically writes it to a file. A bank that
+// could not be read, or that changed on disk since it was last read, is
+// never written.
 func (b *Bank) Write() {
+	if b.readOnly {
+		return
+	}
+	if b.changed() {
+		b.err = fmt.Errorf("bank %s changed on disk, waiting for reload", b.filename)
+		return
+	}
 	content, err := json.MarshalIndent(b, "", "  ")
+	if err == nil {
+		err = writeFile(b.filename, content)
+	}
 	if err != nil {
-		log.Fatal(err)
+		b.err = fmt.Errorf("cannot write bank %s: %w", b.filename, err)
+		return
+	}
+	b.stat()
+	b.err = nil
+}
+
+// SaveAs writes the bank to a new file, where it is saved from then on.
+// Relative paths are resolved from the bank directory. An existing file is
+// never overwritten.
+func (b *Bank) SaveAs(filename string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(filepath.Dir(b.filename), filename)
+	}
+	if _, err := os.Stat(filename); err == nil {
+		return fmt.Errorf("%s already exists", filename)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
 	}
-	err = os.WriteFile(b.filename, content, 0o644)
+	content, err := json.MarshalIndent(b, "", "  ")
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if err := writeFile(filename, content); err != nil {
+		return fmt.Errorf("cannot write bank %s: %w", filename, err)
 	}
+	b.filename = filename
+	b.readOnly = false
+	b.recovery = ""
+	b.pending = nil
+	b.stat()
+	b.err = nil
+	return nil
+}
+
+// Read reads a json and unmarshal its content to the Bank. Older bank
+// versions are migrated after keeping a backup of the file.
+func (b *Bank) Read(filename string) error {
+	return b.read(filename, true)
 }
 
-// Read reads a json and unmarshal its content to the Bank..
-func (b *Bank) Read(filename string) {
+// read reads a bank file, keeping a backup before a migration if asked.
+// Backup files are read without one.
+func (b *Bank) read(filename string, migrationBackup bool) error {
 	f, err := os.Open(filename)
 	if err != nil && errors.Is(err, os.ErrNotExist) {
-		return
+		return nil
 	} else if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer f.Close()
 
-	content, _ := io.ReadAll(f)
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	content, version, err := migrate(content, bankMigrations)
+	if err != nil {
+		return err
+	}
+	if version < BankVersion && migrationBackup {
+		if err := backup(filename, version); err != nil {
+			return fmt.Errorf("cannot backup before migration: %w", err)
+		}
+	}
+
 	err = json.Unmarshal(content, b)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	b.Grids = b.Grids[:cap(b.Grids)]
+	b.Song.normalize()
+	return nil
 }
@@ -3,26 +3,64 @@ package filesystem
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+
+	"signls/core/theory"
 )
 
 // Configuration represents a configuration loaded from a json file.
 type Configuration struct {
-	KeyMap   KeyMap `json:"keymap"`
-	version  string
-	filename string
+	FormatVersion int     `json:"version"`
+	KeyMap        KeyMap  `json:"keymap"`
+	Scales        []Scale `json:"scales"`
+	Theme         string  `json:"theme"`
+	Themes        []Theme `json:"themes"` // User-defined themes.
+	version       string
+	filename      string
+
+	err error // Load or save error.
+}
+
+// Scale represents a user-defined scale. It is either defined by a list of
+// semitone intervals from the root or by a Scala (.scl) file.
+type Scale struct {
+	Name      string `json:"name"`
+	Intervals []int  `json:"intervals,omitempty"`
+	File      string `json:"file,omitempty"`
+}
+
+// NewDefaultScales returns the extra scales always registered before the
+// configured ones. They are not written to the configuration file.
+func NewDefaultScales() []Scale {
+	return []Scale{
+		{Name: "harm min", Intervals: []int{0, 2, 3, 5, 7, 8, 11}},
+		{Name: "melo min", Intervals: []int{0, 2, 3, 5, 7, 9, 11}},
+		{Name: "whole tone", Intervals: []int{0, 2, 4, 6, 8, 10}},
+		{Name: "blues", Intervals: []int{0, 3, 5, 6, 7, 10}},
+		{Name: "hijaz", Intervals: []int{0, 1, 4, 5, 7, 8, 10}},
+		{Name: "hijaz kar", Intervals: []int{0, 1, 4, 5, 7, 8, 11}},
+		{Name: "saba", Intervals: []int{0, 1, 3, 4, 7, 8, 10}},
+	}
 }
 
 // NewConfiguration returns a new default configuration.
 func NewConfiguration(filename, version, keyboard string) Configuration {
 	config := Configuration{
-		KeyMap:   NewDefaultQwertyKeyMap(),
-		version:  version,
-		filename: filename,
+		FormatVersion: ConfigVersion,
+		KeyMap:        NewDefaultQwertyKeyMap(),
+		Scales:        []Scale{},
+		Theme:         DefaultTheme,
+		Themes:        []Theme{},
+		version:       version,
+		filename:      filename,
+	}
+	if err := config.Load(filename); err != nil {
+		config.err = fmt.Errorf("cannot read config %s, using defaults: %w", filename, err)
 	}
-	config.Load(filename)
 
 	if keyboard != "" {
 		switch keyboard {
@@ -34,7 +72,10 @@ func NewConfiguration(filename, version, keyboard string) Configuration {
 			config.KeyMap = NewDefaultAzertyMacKeyMap()
 		}
 	}
-	config.Save()
+	if config.err == nil {
+		config.Save()
+	}
+	config.registerScales()
 
 	return config
 }
@@ -47,32 +88,93 @@ func (c Configuration) Version() string {
 	return c.version
 }
 
+// Err returns the error that occurred while loading or saving the
+// configuration.
+func (c Configuration) Err() error {
+	return c.err
+}
+
 // Save serializes the Configuration and writes it to a file.
 func (c *Configuration) Save() {
 	content, err := json.MarshalIndent(c, "", "  ")
-	if err != nil {
-		log.Fatal(err)
+	if err == nil {
+		err = writeFile(c.filename, content)
 	}
-	err = os.WriteFile(c.filename, content, 0o644)
 	if err != nil {
-		log.Fatal(err)
+		c.err = fmt.Errorf("cannot write config %s: %w", c.filename, err)
 	}
 }
 
-// Load reads a json and unmarshal its content to the Configuration.
-func (c *Configuration) Load(filename string) {
+// Load reads a json and unmarshal its content to the Configuration. Older
+// configuration versions are migrated after keeping a backup of the file.
+func (c *Configuration) Load(filename string) error {
 	f, err := os.Open(filename)
 	if err != nil && errors.Is(err, os.ErrNotExist) {
-		return
+		return nil
 	} else if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer f.Close()
 
-	content, _ := io.ReadAll(f)
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	content, version, err := migrate(content, configMigrations)
+	if err != nil {
+		return err
+	}
+	if version < ConfigVersion {
+		if err := backup(filename, version); err != nil {
+			return fmt.Errorf("cannot backup before migration: %w", err)
+		}
+	}
+
 	err = json.Unmarshal(content, c)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	c.filename = filename
+	return nil
+}
+
+// registerScales makes the default and user-defined scales available to
+// the sequencer. Invalid scales are skipped.
+func (c Configuration) registerScales() {
+	for _, s := range append(NewDefaultScales(), c.Scales...) {
+		scale, name, err := s.load(filepath.Dir(c.filename))
+		if err != nil {
+			log.Printf("cannot load scale %s: %s", s.Name, err)
+			continue
+		}
+		theory.RegisterScale(name, scale)
+	}
+}
+
+// load returns the scale and its name. Relative Scala file paths are
+// resolved from the given directory.
+func (s Scale) load(dir string) (theory.Scale, string, error) {
+	if s.File == "" {
+		scale, err := theory.NewScale(s.Intervals)
+		return scale, s.Name, err
+	}
+
+	path := s.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	description, scale, err := theory.ParseScala(f)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s: %w", s.File, err)
+	}
+	if s.Name != "" {
+		return scale, s.Name, nil
+	}
+	return scale, description, nil
 }
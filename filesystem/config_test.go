@@ -0,0 +1,39 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"testing"
+
+	"signls/core/theory"
+)
+
+func TestRegisterScales(t *testing.T) {
+	config := Configuration{
+		filename: filepath.Join(t.TempDir(), "config.json"),
+		Scales:   []Scale{{Name: "pelog", Intervals: []int{0, 1, 3, 7, 8}}},
+	}
+	config.registerScales()
+
+	for _, s := range append(NewDefaultScales(), config.Scales...) {
+		scale, err := theory.NewScale(s.Intervals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scale.Name() != s.Name {
+			t.Fatalf("scale %s not registered, got %q", s.Name, scale.Name())
+		}
+	}
+}
+
+func TestDefaultScalesNotSaved(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	NewConfiguration(filename, "", "")
+
+	var config Configuration
+	if err := config.Load(filename); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Scales) != 0 {
+		t.Fatalf("default scales written to the configuration: %v", config.Scales)
+	}
+}
@@ -0,0 +1,68 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// maxBackups is the number of rotating backups kept for a file.
+const maxBackups = 3
+
+// writeFile replaces the content of a file atomically. The content is
+// written to a temporary file that replaces the file once complete, so a
+// crash never leaves a partially written file.
+func writeFile(filename string, content []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
+
+// copyFile copies a file atomically.
+func copyFile(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return writeFile(dst, content)
+}
+
+// backupFilename returns the name of a rotating backup, 1 being the
+// newest.
+func backupFilename(filename string, nb int) string {
+	return fmt.Sprintf("%s.%d.bak", filename, nb)
+}
+
+// rotateBackups shifts the backups of a file and copies the file as the
+// newest backup.
+func rotateBackups(filename string) error {
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	for i := maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(backupFilename(filename, i), backupFilename(filename, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return copyFile(filename, backupFilename(filename, 1))
+}
@@ -0,0 +1,95 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBankRecovery(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bank.json")
+
+	bank := New(filename)
+	bank.Save(0, Grid{Tempo: 90, Nodes: []Node{{X: 1, Y: 1, Type: "bang"}}})
+	for i := 0; i <= maxBackups; i++ {
+		bank.Backup()
+	}
+	if _, err := os.Stat(backupFilename(filename, maxBackups+1)); err == nil {
+		t.Fatalf("more than %d backups kept", maxBackups)
+	}
+
+	if err := os.WriteFile(filename, []byte(`{"grids": [`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bank = New(filename)
+	if bank.Err() == nil {
+		t.Fatal("expected a read error")
+	}
+	if bank.Recovery() != backupFilename(filename, 1) {
+		t.Fatalf("expected recovery from newest backup, got %q", bank.Recovery())
+	}
+	if err := bank.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	if bank.Grid(0).Tempo != 90 || len(bank.Grid(0).Nodes) != 1 {
+		t.Fatalf("grid not recovered: %+v", bank.Grid(0))
+	}
+	if _, err := os.Stat(filename + ".corrupt"); err != nil {
+		t.Fatal("unreadable bank not kept aside")
+	}
+
+	bank = New(filename)
+	if err := bank.Err(); err != nil {
+		t.Fatalf("recovered bank not written: %s", err)
+	}
+}
+
+func TestBankReload(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bank.json")
+	bank := New(filename)
+	bank.Save(0, Grid{Tempo: 90})
+	bank.Save(1, Grid{Tempo: 90})
+	bank.Song.Entries[0] = SongEntry{Grid: 0, Bars: 1}
+	bank.Song.Start()
+
+	external := New(filename)
+	external.Grids[0].Tempo = 100
+	external.Grids[1].Tempo = 110
+	external.Song.Entries[0] = SongEntry{Grid: 3, Bars: 2}
+	external.Write()
+	// Make sure the modification is seen on coarse file systems.
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(filename, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bank.Changed() {
+		t.Fatal("external change not detected")
+	}
+	bank.Save(2, Grid{Tempo: 90})
+	if bank.Err() == nil {
+		t.Fatal("changed bank overwritten")
+	}
+
+	changed, err := bank.Reload([]int{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != 0 {
+		t.Fatalf("expected playing grid 0 to wait for reload, got %v", changed)
+	}
+	if bank.Grid(0).Tempo != 90 || bank.Grid(1).Tempo != 110 {
+		t.Fatalf("unexpected reload: %.f %.f", bank.Grid(0).Tempo, bank.Grid(1).Tempo)
+	}
+	if !bank.Song.Playing() || bank.Song.Entries[0].Grid != 3 {
+		t.Fatal("song entries not reloaded or song stopped")
+	}
+	bank.AcceptReload()
+	if bank.Grid(0).Tempo != 100 {
+		t.Fatal("playing grid not reloaded")
+	}
+	if bank.Changed() {
+		t.Fatal("reloaded bank still marked as changed")
+	}
+}
@@ -20,6 +20,7 @@ type KeyMap struct {
 	EditLeft  string `json:"edit_left"`
 
 	EditInput string `json:"edit_input"`
+	Command   string `json:"command"`
 
 	Bank string `json:"bank"`
 
@@ -33,9 +34,10 @@ type KeyMap struct {
 	AddZone   string `json:"add_zone"`
 	AddHole   string `json:"add_hole"`
 
-	Copy  string `json:"copy"`
-	Cut   string `json:"cut"`
-	Paste string `json:"paste"`
+	Copy     string `json:"copy"`
+	Cut      string `json:"cut"`
+	Paste    string `json:"paste"`
+	CopyText string `json:"copy_text"`
 
 	EditNode    string `json:"edit_node"`
 	RemoveNode  string `json:"remove_node"`
@@ -44,6 +46,15 @@ type KeyMap struct {
 	MuteNode    string `json:"mute_node"`
 	MuteAllNode string `json:"mute_all_node"`
 
+	SelectNodes string `json:"select_nodes"`
+
+	Layer string `json:"layer"`
+	Solo  string `json:"solo"`
+
+	GridColor  string `json:"grid_color"`
+	GridNotes  string `json:"grid_notes"`
+	SearchGrid string `json:"search_grid"`
+
 	RootNoteUp   string `json:"root_note_up"`
 	RootNoteDown string `json:"root_note_down"`
 	ScaleUp      string `json:"scale_up"`
@@ -52,6 +63,10 @@ type KeyMap struct {
 	TempoDown    string `json:"tempo_down"`
 
 	Configuration   string `json:"configuration"`
+	Song            string `json:"song"`
+	Patterns        string `json:"patterns"`
+	NoteLog         string `json:"note_log"`
+	Mixer           string `json:"mixer"`
 	FitGridToWindow string `json:"fit_grid_to_window"`
 
 	Cancel string `json:"cancel"`
@@ -81,6 +96,7 @@ func NewDefaultAzertyKeyMap() KeyMap {
 		EditLeft:  "ctrl+left",
 
 		EditInput: ":",
+		Command:   ":",
 
 		Bank: "tab",
 
@@ -98,6 +114,8 @@ func NewDefaultAzertyKeyMap() KeyMap {
 		Cut:   "ctrl+x",
 		Paste: "ctrl+v",
 
+		CopyText: "ctrl+t",
+
 		EditNode:    "enter",
 		RemoveNode:  "backspace",
 		TriggerNode: "!",
@@ -105,6 +123,15 @@ func NewDefaultAzertyKeyMap() KeyMap {
 		MuteNode:    "m",
 		MuteAllNode: "M",
 
+		SelectNodes: "ctrl+f",
+
+		Layer: "l",
+		Solo:  "s",
+
+		GridColor:  "c",
+		GridNotes:  "n",
+		SearchGrid: "f",
+
 		RootNoteUp:   "*",
 		RootNoteDown: "ù",
 		ScaleUp:      "µ",
@@ -113,6 +140,10 @@ func NewDefaultAzertyKeyMap() KeyMap {
 		TempoDown:    ")",
 
 		Configuration:   "f2",
+		Song:            "f3",
+		Patterns:        "f4",
+		NoteLog:         "f5",
+		Mixer:           "f6",
 		FitGridToWindow: "f10",
 
 		Cancel: "esc",
@@ -144,6 +175,7 @@ func NewDefaultAzertyMacKeyMap() KeyMap {
 		EditLeft:  "ctrl+left",
 
 		EditInput: ":",
+		Command:   ":",
 
 		Bank: "tab",
 
@@ -161,6 +193,8 @@ func NewDefaultAzertyMacKeyMap() KeyMap {
 		Cut:   "ctrl+x",
 		Paste: "ctrl+v",
 
+		CopyText: "ctrl+t",
+
 		EditNode:    "enter",
 		RemoveNode:  "backspace",
 		TriggerNode: "=",
@@ -168,6 +202,15 @@ func NewDefaultAzertyMacKeyMap() KeyMap {
 		MuteNode:    "m",
 		MuteAllNode: "M",
 
+		SelectNodes: "ctrl+f",
+
+		Layer: "l",
+		Solo:  "s",
+
+		GridColor:  "c",
+		GridNotes:  "n",
+		SearchGrid: "f",
+
 		RootNoteUp:   "`",
 		RootNoteDown: "ù",
 		ScaleUp:      "£",
@@ -176,6 +219,10 @@ func NewDefaultAzertyMacKeyMap() KeyMap {
 		TempoDown:    ")",
 
 		Configuration:   "f2",
+		Song:            "f3",
+		Patterns:        "f4",
+		NoteLog:         "f5",
+		Mixer:           "f6",
 		FitGridToWindow: "f10",
 
 		Cancel: "esc",
@@ -206,6 +253,7 @@ func NewDefaultQwertyKeyMap() KeyMap {
 		EditLeft:  "ctrl+left",
 
 		EditInput: ".",
+		Command:   ".",
 
 		Bank: "tab",
 
@@ -223,6 +271,8 @@ func NewDefaultQwertyKeyMap() KeyMap {
 		Cut:   "ctrl+x",
 		Paste: "ctrl+v",
 
+		CopyText: "ctrl+t",
+
 		EditNode:    "enter",
 		RemoveNode:  "backspace",
 		TriggerNode: "/",
@@ -230,6 +280,15 @@ func NewDefaultQwertyKeyMap() KeyMap {
 		MuteNode:    "m",
 		MuteAllNode: "M",
 
+		SelectNodes: "ctrl+f",
+
+		Layer: "l",
+		Solo:  "s",
+
+		GridColor:  "c",
+		GridNotes:  "n",
+		SearchGrid: "f",
+
 		RootNoteUp:   "'",
 		RootNoteDown: ";",
 		ScaleUp:      "\"",
@@ -238,6 +297,10 @@ func NewDefaultQwertyKeyMap() KeyMap {
 		TempoDown:    "-",
 
 		Configuration:   "f2",
+		Song:            "f3",
+		Patterns:        "f4",
+		NoteLog:         "f5",
+		Mixer:           "f6",
 		FitGridToWindow: "f10",
 
 		Cancel: "esc",
@@ -269,6 +332,7 @@ func NewDefaultQwertyMacKeyMap() KeyMap {
 		EditLeft:  "ctrl+left",
 
 		EditInput: ".",
+		Command:   ".",
 
 		Bank: "tab",
 
@@ -286,6 +350,8 @@ func NewDefaultQwertyMacKeyMap() KeyMap {
 		Cut:   "ctrl+x",
 		Paste: "ctrl+v",
 
+		CopyText: "ctrl+t",
+
 		EditNode:    "enter",
 		RemoveNode:  "backspace",
 		TriggerNode: "/",
@@ -293,6 +359,15 @@ func NewDefaultQwertyMacKeyMap() KeyMap {
 		MuteNode:    "m",
 		MuteAllNode: "M",
 
+		SelectNodes: "ctrl+f",
+
+		Layer: "l",
+		Solo:  "s",
+
+		GridColor:  "c",
+		GridNotes:  "n",
+		SearchGrid: "f",
+
 		RootNoteUp:   "'",
 		RootNoteDown: ";",
 		ScaleUp:      "\"",
@@ -301,6 +376,10 @@ func NewDefaultQwertyMacKeyMap() KeyMap {
 		TempoDown:    "-",
 
 		Configuration:   "f2",
+		Song:            "f3",
+		Patterns:        "f4",
+		NoteLog:         "f5",
+		Mixer:           "f6",
 		FitGridToWindow: "f10",
 
 		Cancel: "esc",
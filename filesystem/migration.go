@@ -0,0 +1,143 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"signls/core/music"
+)
+
+const (
+	// BankVersion is the current bank file format version.
+	BankVersion = 1
+	// ConfigVersion is the current configuration file format version.
+	ConfigVersion = 1
+
+	legacyInfiniteLength = 127
+)
+
+// migration upgrades a decoded json document from one version to the next.
+type migration func(doc map[string]any) error
+
+// bankMigrations upgrades bank files, the migration at index i going from
+// version i to version i+1.
+var bankMigrations = []migration{
+	migrateLegacyBank,
+}
+
+// configMigrations upgrades configuration files, the migration at index i
+// going from version i to version i+1.
+var configMigrations = []migration{
+	migrateLegacyConfig,
+}
+
+// migrate upgrades json content to the latest version. It returns the
+// upgraded content and the version it was upgraded from. Content written by
+// a newer version cannot be read.
+func migrate(content []byte, migrations []migration) ([]byte, int, error) {
+	doc := map[string]any{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, 0, err
+	}
+
+	version := 0
+	if v, ok := doc["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > len(migrations) {
+		return nil, version, fmt.Errorf(
+			"file version %d is newer than the supported version %d",
+			version,
+			len(migrations),
+		)
+	}
+	if version == len(migrations) {
+		return content, version, nil
+	}
+
+	for i, m := range migrations[version:] {
+		if err := m(doc); err != nil {
+			return nil, version, fmt.Errorf("cannot migrate to version %d: %w", version+i+1, err)
+		}
+	}
+	doc["version"] = len(migrations)
+	content, err := json.Marshal(doc)
+	return content, version, err
+}
+
+// backup copies a file before migrating it, keeping the first backup of a
+// given version.
+func backup(filename string, version int) error {
+	backupFilename := fmt.Sprintf("%s.v%d.bak", filename, version)
+	if _, err := os.Stat(backupFilename); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return copyFile(filename, backupFilename)
+}
+
+// migrateLegacyBank upgrades banks written before versioning. Notes with
+// the maximum length were played as infinite notes, ratchets, bend range
+// and control shapes did not exist.
+func migrateLegacyBank(doc map[string]any) error {
+	grids, _ := doc["grids"].([]any)
+	for _, g := range grids {
+		grid, ok := g.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, ok := grid["bend_range"]; !ok {
+			grid["bend_range"] = music.DefaultBendRange
+		}
+
+		nodes, _ := grid["nodes"].([]any)
+		for _, n := range nodes {
+			node, ok := n.(map[string]any)
+			if !ok {
+				continue
+			}
+			note, ok := node["note"].(map[string]any)
+			if !ok {
+				continue
+			}
+			if _, ok := note["tie"]; !ok {
+				length, _ := note["length"].(map[string]any)
+				note["tie"] = length["Value"] == float64(legacyInfiniteLength)
+			}
+			if _, ok := note["ratchet"]; !ok {
+				note["ratchet"] = map[string]any{"Value": 1, "Amount": 0}
+			}
+			controls, _ := note["controls"].([]any)
+			for _, c := range controls {
+				migrateLegacyControl(c)
+			}
+		}
+	}
+	return nil
+}
+
+// migrateLegacyControl sets the shape end and attack of a control written
+// before control shapes to their defaults for the control type.
+func migrateLegacyControl(c any) {
+	control, ok := c.(map[string]any)
+	if !ok {
+		return
+	}
+	if _, ok := control["end"]; ok {
+		return
+	}
+	controlType, _ := control["type"].(float64)
+	cc := music.NewCC(nil, music.SilentControlType)
+	cc.SetType(int(controlType))
+	control["end"] = map[string]any{"Value": cc.End.Value(), "Amount": 0}
+	control["attack"] = cc.Attack
+}
+
+// migrateLegacyConfig upgrades configurations written before versioning.
+// Missing keys keep their default values.
+func migrateLegacyConfig(doc map[string]any) error {
+	return nil
+}
@@ -0,0 +1,120 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const legacyBank = `{
+  "grids": [
+    {
+      "nodes": [
+        {"x": 1, "y": 1, "type": "bang", "note": {"length": {"Value": 127, "Amount": 0}}},
+        {"x": 2, "y": 1, "type": "bang", "note": {"length": {"Value": 6, "Amount": 0}, "controls": [{"type": 1, "controller": 3, "value": {"Value": 9, "Amount": 0}}]}}
+      ],
+      "tempo": 120, "height": 20, "width": 20
+    }
+  ],
+  "active": 0
+}`
+
+func TestMigrationVersions(t *testing.T) {
+	if len(bankMigrations) != BankVersion {
+		t.Fatalf("%d bank migrations for version %d", len(bankMigrations), BankVersion)
+	}
+	if len(configMigrations) != ConfigVersion {
+		t.Fatalf("%d config migrations for version %d", len(configMigrations), ConfigVersion)
+	}
+}
+
+func TestBankMigration(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bank.json")
+	if err := os.WriteFile(filename, []byte(legacyBank), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	bank := New(filename)
+	if err := bank.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if bank.FormatVersion != BankVersion {
+		t.Fatalf("got version %d, want %d", bank.FormatVersion, BankVersion)
+	}
+	nodes := bank.Grids[0].Nodes
+	if !nodes[0].Note.Tie || nodes[1].Note.Tie {
+		t.Fatalf("legacy infinite length must be migrated to a tie")
+	}
+	if nodes[0].Note.Ratchet.Value != 1 {
+		t.Fatalf("got ratchet %d, want 1", nodes[0].Note.Ratchet.Value)
+	}
+	if c := nodes[1].Note.Controls[0]; c.End.Value != 127 || c.Attack != 50 {
+		t.Fatalf("got control end %d and attack %d, want the defaults", c.End.Value, c.Attack)
+	}
+	if _, err := os.Stat(filename + ".v0.bak"); err != nil {
+		t.Fatalf("missing backup: %s", err)
+	}
+}
+
+func TestLegacyTieMigration(t *testing.T) {
+	tests := []struct {
+		note map[string]any
+		tie  bool
+	}{
+		{map[string]any{"length": map[string]any{"Value": float64(127)}}, true},
+		{map[string]any{"length": map[string]any{"Value": float64(96)}}, false},
+		{map[string]any{"length": map[string]any{"Value": float64(127)}, "tie": false}, false},
+		{map[string]any{}, false},
+	}
+	for _, tt := range tests {
+		doc := map[string]any{"grids": []any{
+			map[string]any{"nodes": []any{map[string]any{"note": tt.note}}},
+		}}
+		if err := migrateLegacyBank(doc); err != nil {
+			t.Fatal(err)
+		}
+		if tt.note["tie"] != tt.tie {
+			t.Fatalf("%v: got tie %v, want %v", tt.note["length"], tt.note["tie"], tt.tie)
+		}
+	}
+}
+
+func TestBankRecoveryMigration(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bank.json")
+	if err := os.WriteFile(filename, []byte(`{"grids": [`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	backup := backupFilename(filename, 1)
+	if err := os.WriteFile(backup, []byte(legacyBank), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	bank := New(filename)
+	if bank.Recovery() != backup {
+		t.Fatalf("expected recovery from %s, got %q", backup, bank.Recovery())
+	}
+	if err := bank.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := filepath.Glob(backup + ".v*.bak"); len(matches) > 0 {
+		t.Fatalf("backup file backed up before migration: %v", matches)
+	}
+}
+
+func TestBankNewerVersion(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bank.json")
+	content := []byte(`{"version": 999, "grids": []}`)
+	if err := os.WriteFile(filename, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	bank := New(filename)
+	if bank.Err() == nil {
+		t.Fatalf("newer bank version must not be read")
+	}
+	bank.Save(0, NewGrid())
+	written, _ := os.ReadFile(filename)
+	if string(written) != string(content) {
+		t.Fatalf("unreadable bank must not be overwritten")
+	}
+}
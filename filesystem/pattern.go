@@ -0,0 +1,123 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	patternExtension     = ".json"
+	textPatternExtension = ".txt"
+)
+
+// Pattern holds a single grid that can be shared between banks.
+type Pattern struct {
+	FormatVersion int  `json:"version"`
+	Grid          Grid `json:"grid"`
+}
+
+// ExportPattern writes a grid to its own pattern file. Patterns with a
+// .txt extension use the text format.
+func ExportPattern(filename string, grid Grid) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+	if filepath.Ext(filename) == textPatternExtension {
+		text, err := EncodeText(grid)
+		if err != nil {
+			return err
+		}
+		return writeFile(filename, []byte(text))
+	}
+	content, err := json.MarshalIndent(Pattern{
+		FormatVersion: BankVersion,
+		Grid:          grid,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(filename, content)
+}
+
+// ImportPattern reads a grid from a pattern file. Patterns follow the bank
+// versions and are migrated the same way.
+func ImportPattern(filename string) (Grid, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return Grid{}, err
+	}
+	if filepath.Ext(filename) == textPatternExtension {
+		return DecodeText(string(content))
+	}
+
+	pattern := struct {
+		FormatVersion int             `json:"version"`
+		Grid          json.RawMessage `json:"grid"`
+	}{}
+	if err := json.Unmarshal(content, &pattern); err != nil {
+		return Grid{}, err
+	}
+	if pattern.Grid == nil {
+		return Grid{}, errors.New("invalid pattern")
+	}
+
+	// Wrap the pattern as a single grid bank for migrating it.
+	content, err = json.Marshal(map[string]any{
+		"version": pattern.FormatVersion,
+		"grids":   []json.RawMessage{pattern.Grid},
+	})
+	if err != nil {
+		return Grid{}, err
+	}
+	content, _, err = migrate(content, bankMigrations)
+	if err != nil {
+		return Grid{}, err
+	}
+	bank := struct {
+		Grids []Grid `json:"grids"`
+	}{}
+	if err := json.Unmarshal(content, &bank); err != nil {
+		return Grid{}, err
+	}
+	if len(bank.Grids) != 1 {
+		return Grid{}, errors.New("invalid pattern")
+	}
+	return bank.Grids[0], nil
+}
+
+// ListPatterns returns the pattern names of a directory, sorted by name.
+// Text patterns keep their extension.
+func ListPatterns(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	patterns := []string{}
+	for _, e := range entries {
+		switch {
+		case e.IsDir():
+			continue
+		case filepath.Ext(e.Name()) == patternExtension:
+			patterns = append(patterns, strings.TrimSuffix(e.Name(), patternExtension))
+		case filepath.Ext(e.Name()) == textPatternExtension:
+			patterns = append(patterns, e.Name())
+		}
+	}
+	sort.Strings(patterns)
+	return patterns, nil
+}
+
+// PatternFilename returns the file name of a pattern in a directory.
+func PatternFilename(dir, name string) string {
+	if filepath.Ext(name) == textPatternExtension {
+		return filepath.Join(dir, name)
+	}
+	return filepath.Join(dir, name+patternExtension)
+}
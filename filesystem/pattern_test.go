@@ -0,0 +1,59 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPattern(t *testing.T) {
+	dir := t.TempDir()
+	grid := NewGrid()
+	grid.Nodes = []Node{{X: 3, Y: 4, Type: "bang", Note: Note{Tie: true}}}
+
+	if err := ExportPattern(PatternFilename(dir, "drums"), grid); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportPattern(PatternFilename(dir, "bass.txt"), grid); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `{"grid": {"nodes": [{"x": 1, "y": 1, "type": "bang", "note": {"length": {"Value": 127}}}]}}`
+	if err := os.WriteFile(filepath.Join(dir, "legacy.json"), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte{}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	patterns, err := ListPatterns(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 3 || patterns[0] != "bass.txt" || patterns[1] != "drums" || patterns[2] != "legacy" {
+		t.Fatalf("got patterns %v", patterns)
+	}
+
+	imported, err := ImportPattern(PatternFilename(dir, "drums"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported.Nodes) != 1 || imported.Nodes[0].X != 3 || !imported.Nodes[0].Note.Tie {
+		t.Fatalf("pattern not round tripped: %+v", imported.Nodes)
+	}
+
+	imported, err = ImportPattern(PatternFilename(dir, "bass.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported.Nodes) != 1 || imported.Nodes[0].Y != 4 || !imported.Nodes[0].Note.Tie {
+		t.Fatalf("text pattern not round tripped: %+v", imported.Nodes)
+	}
+
+	imported, err = ImportPattern(PatternFilename(dir, "legacy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !imported.Nodes[0].Note.Tie {
+		t.Fatalf("legacy pattern not migrated")
+	}
+}
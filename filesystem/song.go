@@ -0,0 +1,133 @@
+package filesystem
+
+const (
+	// MaxSongEntries is the number of entry slots in a song.
+	MaxSongEntries = 16
+)
+
+// Song holds an ordered arrangement of bank grids. Entries play in order
+// and the song loops back from the loop end entry to the loop start entry.
+type Song struct {
+	Entries   []SongEntry `json:"entries"`
+	LoopStart int         `json:"loop_start"`
+	LoopEnd   int         `json:"loop_end"`
+
+	playing bool
+	entry   int
+	repeat  int
+	load    bool // The current entry grid still needs to be loaded.
+}
+
+// SongEntry plays a bank grid for a number of bars, repeated a number of
+// times. An entry with no bars is disabled and skipped.
+type SongEntry struct {
+	Grid   int `json:"grid"`
+	Bars   int `json:"bars"`
+	Repeat int `json:"repeat"`
+}
+
+// Enabled returns true if the entry is part of the song.
+func (e SongEntry) Enabled() bool {
+	return e.Bars > 0
+}
+
+// NewSong creates an empty song.
+func NewSong() Song {
+	return Song{
+		Entries: make([]SongEntry, MaxSongEntries),
+		LoopEnd: MaxSongEntries - 1,
+	}
+}
+
+// Playing returns true if the song is playing.
+func (s *Song) Playing() bool {
+	return s.playing
+}
+
+// Entry returns the index of the entry currently played.
+func (s *Song) Entry() int {
+	return s.entry
+}
+
+// Start starts the song from its first entry.
+func (s *Song) Start() {
+	first := s.next(-1, len(s.Entries)-1)
+	if first < 0 {
+		return
+	}
+	s.playing = true
+	s.entry = first
+	s.repeat = 0
+	s.load = true
+}
+
+// Stop stops the song.
+func (s *Song) Stop() {
+	s.playing = false
+	s.load = false
+}
+
+// Update advances the song given the number of bars played by the current
+// grid. It returns the grid to load when the song moves to a new entry or
+// repeats the current one.
+func (s *Song) Update(bars int) (int, bool) {
+	if !s.playing {
+		return 0, false
+	}
+	if s.load {
+		s.load = false
+		return s.Entries[s.entry].Grid, true
+	}
+	if !s.Entries[s.entry].Enabled() {
+		// The current entry was disabled while playing.
+		s.repeat = max(s.Entries[s.entry].Repeat, 1)
+	} else if bars < s.Entries[s.entry].Bars {
+		return 0, false
+	}
+
+	s.repeat++
+	if s.repeat < max(s.Entries[s.entry].Repeat, 1) {
+		return s.Entries[s.entry].Grid, true
+	}
+
+	next := s.next(s.entry, min(s.LoopEnd, len(s.Entries)-1))
+	if next < 0 || s.entry >= s.LoopEnd {
+		next = s.next(s.LoopStart-1, min(s.LoopEnd, len(s.Entries)-1))
+	}
+	if next < 0 {
+		s.Stop()
+		return 0, false
+	}
+	s.entry = next
+	s.repeat = 0
+	return s.Entries[s.entry].Grid, true
+}
+
+// next returns the index of the first enabled entry after the given one,
+// up to the last index, or -1.
+func (s *Song) next(current, last int) int {
+	for i := current + 1; i <= last && i < len(s.Entries); i++ {
+		if s.Entries[i].Enabled() {
+			return i
+		}
+	}
+	return -1
+}
+
+// arrange replaces the song entries and loop with the ones of another song,
+// keeping the playing state.
+func (s *Song) arrange(other Song) {
+	s.Entries = other.Entries
+	s.LoopStart = other.LoopStart
+	s.LoopEnd = other.LoopEnd
+}
+
+// normalize ensures the song has all its entry slots after being read.
+func (s *Song) normalize() {
+	for len(s.Entries) < MaxSongEntries {
+		s.Entries = append(s.Entries, SongEntry{})
+	}
+	s.Entries = s.Entries[:MaxSongEntries]
+	s.LoopStart = max(min(s.LoopStart, MaxSongEntries-1), 0)
+	s.LoopEnd = max(min(s.LoopEnd, MaxSongEntries-1), s.LoopStart)
+}
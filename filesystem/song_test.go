@@ -0,0 +1,32 @@
+package filesystem
+
+import "testing"
+
+func TestSong(t *testing.T) {
+	song := NewSong()
+	song.Entries[0] = SongEntry{Grid: 2, Bars: 4, Repeat: 2}
+	song.Entries[1] = SongEntry{Grid: 5, Bars: 2, Repeat: 1}
+	song.Entries[3] = SongEntry{Grid: 7, Bars: 1, Repeat: 1}
+	song.LoopStart = 1
+	song.LoopEnd = 3
+
+	song.Start()
+	tests := []struct {
+		bars   int
+		grid   int
+		loaded bool
+	}{
+		{0, 2, true},  // first entry
+		{3, 0, false}, // still playing
+		{4, 2, true},  // repeat
+		{4, 5, true},  // next entry
+		{2, 7, true},  // skips disabled entry
+		{1, 5, true},  // loops back to loop start
+	}
+	for i, tt := range tests {
+		grid, loaded := song.Update(tt.bars)
+		if loaded != tt.loaded || grid != tt.grid {
+			t.Fatalf("step %d: got grid %d (%t), want grid %d (%t)", i, grid, loaded, tt.grid, tt.loaded)
+		}
+	}
+}
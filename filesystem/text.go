@@ -0,0 +1,342 @@
+package filesystem
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/midi"
+)
+
+// The text format describes a grid with a header of grid parameters, a map
+// with one two characters cell per node (node letter, lowercase when muted,
+// followed by its direction symbol) and a parameter section per node. Node
+// parameters only list the values that differ from a new node.
+//
+//	signls grid 1
+//	tempo 120
+//	width 4
+//	height 2
+//
+//	map
+//	B┏......
+//	..S╸....
+//
+//	@0,0
+//	note.key.Key 64
+const (
+	textHeader    = "signls grid"
+	textMap       = "map"
+	textEmptyCell = ".."
+	textNode      = "@"
+)
+
+// EncodeText returns the text representation of a grid.
+func EncodeText(grid Grid) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d\n", textHeader, BankVersion)
+
+	params := grid
+	params.Nodes = nil
+	doc, err := toDoc(params)
+	if err != nil {
+		return "", err
+	}
+	delete(doc, "nodes")
+	writeParams(&b, flatten(doc), nil)
+
+	cells := make([][]string, grid.Height)
+	for y := range cells {
+		cells[y] = make([]string, grid.Width)
+		for x := range cells[y] {
+			cells[y][x] = textEmptyCell
+		}
+	}
+	for _, n := range grid.Nodes {
+		if n.Y < 0 || n.Y >= grid.Height || n.X < 0 || n.X >= grid.Width {
+			continue
+		}
+		t, ok := FindNodeType(n.Type)
+		if !ok {
+			return "", fmt.Errorf("unknown node type %s", n.Type)
+		}
+		letter := t.Letter()
+		if n.Muted {
+			letter = unicode.ToLower(letter)
+		}
+		cells[n.Y][n.X] = string(letter) + common.Direction(n.Direction).Symbol()
+	}
+	fmt.Fprintf(&b, "\n%s\n", textMap)
+	for _, row := range cells {
+		fmt.Fprintln(&b, strings.Join(row, ""))
+	}
+
+	for _, n := range grid.Nodes {
+		doc, err := nodeParams(n)
+		if err != nil {
+			return "", err
+		}
+		base, err := baseNode(n.Type)
+		if err != nil {
+			return "", err
+		}
+		params := flatten(doc)
+		if !hasChanges(params, flatten(base)) {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s%d,%d\n", textNode, n.X, n.Y)
+		writeParams(&b, params, flatten(base))
+	}
+
+	return b.String(), nil
+}
+
+// DecodeText parses the text representation of a grid.
+func DecodeText(text string) (Grid, error) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	if !scanner.Scan() {
+		return Grid{}, errors.New("empty grid text")
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(scanner.Text(), textHeader)))
+	if !strings.HasPrefix(scanner.Text(), textHeader) || err != nil {
+		return Grid{}, fmt.Errorf("missing '%s <version>' header", textHeader)
+	}
+
+	// Missing grid parameters keep the new grid values.
+	grid, err := toDoc(NewGrid())
+	if err != nil {
+		return Grid{}, err
+	}
+	nodes := []map[string]any{}
+	positions := map[string]map[string]any{}
+	var current map[string]any // Node receiving parameters.
+	inMap, y := false, 0
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRightFunc(scanner.Text(), unicode.IsSpace)
+		switch {
+		case inMap && text != "" && !strings.HasPrefix(text, textNode):
+			row, err := decodeRow(text, y)
+			if err != nil {
+				return Grid{}, fmt.Errorf("line %d: %w", line+1, err)
+			}
+			for _, n := range row {
+				positions[fmt.Sprintf("%v,%v", n["x"], n["y"])] = n
+			}
+			nodes = append(nodes, row...)
+			y++
+			continue
+		case text == "" || strings.HasPrefix(text, "#"):
+			inMap = false
+			continue
+		case text == textMap:
+			inMap = true
+			continue
+		case strings.HasPrefix(text, textNode):
+			inMap = false
+			n, ok := positions[strings.TrimPrefix(text, textNode)]
+			if !ok {
+				return Grid{}, fmt.Errorf("line %d: no node at %s", line+1, strings.TrimPrefix(text, textNode))
+			}
+			current = n
+			continue
+		}
+
+		path, raw, _ := strings.Cut(strings.TrimSpace(text), " ")
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return Grid{}, fmt.Errorf("line %d: invalid value for %s: %w", line+1, path, err)
+		}
+		if current != nil {
+			setPath(current, strings.Split(path, "."), value)
+		} else {
+			setPath(grid, strings.Split(path, "."), value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Grid{}, err
+	}
+
+	nodeDocs := make([]any, len(nodes))
+	for i, n := range nodes {
+		nodeDocs[i] = n
+	}
+	grid["nodes"] = nodeDocs
+	content, err := json.Marshal(map[string]any{
+		"version": version,
+		"grids":   []any{grid},
+	})
+	if err != nil {
+		return Grid{}, err
+	}
+	content, _, err = migrate(content, bankMigrations)
+	if err != nil {
+		return Grid{}, err
+	}
+	bank := struct {
+		Grids []Grid `json:"grids"`
+	}{}
+	if err := json.Unmarshal(content, &bank); err != nil {
+		return Grid{}, err
+	}
+	return bank.Grids[0], nil
+}
+
+// decodeRow returns the nodes of a map row, starting from their new node
+// parameters.
+func decodeRow(text string, y int) ([]map[string]any, error) {
+	cells := []rune(text)
+	if len(cells)%2 != 0 {
+		return nil, errors.New("map cells are two characters wide")
+	}
+	nodes := []map[string]any{}
+	for i := 0; i < len(cells); i += 2 {
+		cell := string(cells[i : i+2])
+		if cell == textEmptyCell {
+			continue
+		}
+		nodeType := ""
+		for _, t := range nodeTypes {
+			if t.Letter() == unicode.ToUpper(cells[i]) {
+				nodeType = t.Name
+			}
+		}
+		direction, ok := common.DirectionFromSymbol(string(cells[i+1]))
+		if nodeType == "" || !ok {
+			return nil, fmt.Errorf("invalid cell %s", cell)
+		}
+		n, err := baseNode(nodeType)
+		if err != nil {
+			return nil, err
+		}
+		n["x"] = i / 2
+		n["y"] = y
+		n["type"] = nodeType
+		n["direction"] = int(direction)
+		n["muted"] = unicode.IsLower(cells[i])
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// baseNode returns the parameters of a new node of the given type. Only
+// emitters play notes.
+func baseNode(nodeType string) (map[string]any, error) {
+	n := Node{Params: map[string]Param{}}
+	if nodeType != "hole" {
+		n.Note = NewNote(*music.NewNote(nil, &midi.Device{}))
+		// New notes use the last used channel.
+		n.Note.Channel = Param{}
+	}
+	return nodeParams(n)
+}
+
+// nodeParams returns the node parameters that are not shown on the map.
+func nodeParams(n Node) (map[string]any, error) {
+	doc, err := toDoc(n)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"x", "y", "type", "direction", "muted"} {
+		delete(doc, key)
+	}
+	return doc, nil
+}
+
+// toDoc converts a value to a generic json document.
+func toDoc(v any) (map[string]any, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]any{}
+	return doc, json.Unmarshal(content, &doc)
+}
+
+// flatten returns the json encoded values of a document by dotted path.
+// Empty objects and arrays are kept as values.
+func flatten(doc map[string]any) map[string]string {
+	values := map[string]string{}
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if len(v) == 0 && path != "" {
+				values[path] = "{}"
+			}
+			for k, child := range v {
+				walk(strings.TrimPrefix(path+"."+k, "."), child)
+			}
+		case []any:
+			if len(v) == 0 {
+				values[path] = "[]"
+			}
+			for i, child := range v {
+				walk(fmt.Sprintf("%s.%d", path, i), child)
+			}
+		default:
+			content, _ := json.Marshal(v)
+			values[path] = string(content)
+		}
+	}
+	walk("", doc)
+	return values
+}
+
+// setPath sets a value in a generic json document at a dotted path.
+func setPath(doc map[string]any, path []string, value any) {
+	var set func(container any, path []string) any
+	set = func(container any, path []string) any {
+		if len(path) == 0 {
+			return value
+		}
+		if i, err := strconv.Atoi(path[0]); err == nil && i >= 0 {
+			list, _ := container.([]any)
+			for len(list) <= i {
+				list = append(list, nil)
+			}
+			list[i] = set(list[i], path[1:])
+			return list
+		}
+		obj, ok := container.(map[string]any)
+		if !ok {
+			obj = map[string]any{}
+		}
+		obj[path[0]] = set(obj[path[0]], path[1:])
+		return obj
+	}
+	doc[path[0]] = set(doc[path[0]], path[1:])
+}
+
+// hasChanges returns true if some values differ from the base values.
+func hasChanges(values, base map[string]string) bool {
+	for path, v := range values {
+		if b, ok := base[path]; !ok || b != v {
+			return true
+		}
+	}
+	return false
+}
+
+// writeParams writes the values that differ from the base values, sorted
+// by path.
+func writeParams(b *strings.Builder, values, base map[string]string) {
+	paths := make([]string, 0, len(values))
+	for path, v := range values {
+		if bv, ok := base[path]; ok && bv == v {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(b, "%s %s\n", path, values[path])
+	}
+}
@@ -0,0 +1,221 @@
+package filesystem
+
+import (
+	"fmt"
+)
+
+// DefaultTheme is the name of the theme used by a new configuration.
+const DefaultTheme = "default"
+
+// Theme holds the user interface colors by element name. Colors are ANSI
+// color codes (0-255) or hex values. An element with a _light suffix sets
+// the element color for terminals with a light background.
+type Theme struct {
+	Name   string            `json:"name"`
+	Base   string            `json:"base,omitempty"` // Theme completing the missing colors.
+	Colors map[string]string `json:"colors"`
+}
+
+// Color returns the color of an element, or an empty string.
+func (t Theme) Color(element string) string {
+	return t.Colors[element]
+}
+
+// NewThemePresets returns the built-in themes. The default theme defines
+// every element.
+func NewThemePresets() []Theme {
+	return []Theme{
+		{
+			Name: DefaultTheme,
+			Colors: map[string]string{
+				// Nodes, by node name.
+				"bang":      "165",
+				"euclid":    "162",
+				"pass":      "35",
+				"spread":    "56",
+				"cycle":     "63",
+				"dice":      "33",
+				"toll":      "39",
+				"zone":      "197",
+				"hole":      "124",
+				"node_text": "15",
+
+				// Grid.
+				"grid":              "234",
+				"grid_light":        "254",
+				"cursor":            "190",
+				"cursor_text":       "0",
+				"destination":       "160",
+				"destination_text":  "15",
+				"selection":         "238",
+				"selection_text":    "244",
+				"muted":             "247",
+				"muted_text":        "236",
+				"active":            "15",
+				"active_light":      "0",
+				"active_text":       "0",
+				"active_text_light": "15",
+
+				// Controls.
+				"highlight":   "190",
+				"error":       "197",
+				"bank":        "79",
+				"bank_alt":    "85",
+				"bank_text":   "0",
+				"bank_active": "15",
+				"bank_queued": "190",
+				"bank_layer":  "141",
+				"bank_muted":  "240",
+				"bank_solo":   "214",
+
+				// Bank grid color tags.
+				"tag_1": "197",
+				"tag_2": "208",
+				"tag_3": "220",
+				"tag_4": "113",
+				"tag_5": "80",
+				"tag_6": "69",
+				"tag_7": "141",
+				"tag_8": "211",
+			},
+		},
+		{
+			// Paul Tol's muted palette, distinguishable with the common
+			// color vision deficiencies.
+			Name: "colorblind",
+			Colors: map[string]string{
+				"bang":        "#CC6677",
+				"euclid":      "#332288",
+				"pass":        "#117733",
+				"spread":      "#DDCC77",
+				"spread_text": "0",
+				"cycle":       "#88CCEE",
+				"cycle_text":  "0",
+				"dice":        "#882255",
+				"toll":        "#44AA99",
+				"toll_text":   "0",
+				"zone":        "#AA4499",
+				"hole":        "#999933",
+				"hole_text":   "0",
+
+				"cursor":      "#DDDDDD",
+				"destination": "#EE7733",
+				"highlight":   "#EE7733",
+				"error":       "#EE7733",
+				"bank":        "#44AA99",
+				"bank_alt":    "#88CCEE",
+				"bank_queued": "#DDCC77",
+				"bank_layer":  "#AA4499",
+				"bank_solo":   "#EE7733",
+
+				"tag_1": "#CC6677",
+				"tag_2": "#EE7733",
+				"tag_3": "#DDCC77",
+				"tag_4": "#117733",
+				"tag_5": "#44AA99",
+				"tag_6": "#88CCEE",
+				"tag_7": "#332288",
+				"tag_8": "#AA4499",
+			},
+		},
+		{
+			// Only uses the 4 gray levels available on 16 color terminals.
+			Name: "monochrome",
+			Colors: map[string]string{
+				"bang":      "7",
+				"euclid":    "7",
+				"pass":      "7",
+				"spread":    "7",
+				"cycle":     "7",
+				"dice":      "7",
+				"toll":      "7",
+				"zone":      "7",
+				"hole":      "8",
+				"node_text": "0",
+
+				"grid":              "0",
+				"grid_light":        "15",
+				"cursor":            "15",
+				"cursor_text":       "0",
+				"destination":       "15",
+				"destination_text":  "0",
+				"selection":         "8",
+				"selection_text":    "7",
+				"muted":             "8",
+				"muted_text":        "0",
+				"active":            "15",
+				"active_light":      "0",
+				"active_text":       "0",
+				"active_text_light": "15",
+
+				"highlight":   "15",
+				"error":       "15",
+				"bank":        "7",
+				"bank_alt":    "8",
+				"bank_text":   "0",
+				"bank_active": "15",
+				"bank_queued": "15",
+				"bank_layer":  "7",
+				"bank_muted":  "8",
+				"bank_solo":   "15",
+
+				"tag_1": "15",
+				"tag_2": "15",
+				"tag_3": "15",
+				"tag_4": "15",
+				"tag_5": "15",
+				"tag_6": "15",
+				"tag_7": "15",
+				"tag_8": "15",
+			},
+		},
+	}
+}
+
+// ResolveTheme returns the configured theme, completed by its base themes.
+// User-defined themes take precedence over the built-in ones. An unknown
+// theme falls back to the default one.
+func (c Configuration) ResolveTheme() (Theme, error) {
+	themes, errs := resolveThemes(append(NewThemePresets(), c.Themes...))
+	if err, ok := errs[c.Theme]; ok {
+		return themes[DefaultTheme], err
+	}
+	theme, ok := themes[c.Theme]
+	if !ok {
+		return themes[DefaultTheme], fmt.Errorf("unknown theme %s", c.Theme)
+	}
+	return theme, nil
+}
+
+// resolveThemes completes the themes with the colors of their base, by
+// name. A theme base is looked up among the themes declared before it, so
+// a theme can extend the built-in theme it replaces. Themes with an
+// unknown base are returned as errors.
+func resolveThemes(themes []Theme) (map[string]Theme, map[string]error) {
+	resolved := map[string]Theme{}
+	errs := map[string]error{}
+	for _, theme := range themes {
+		base := theme.Base
+		if _, ok := resolved[DefaultTheme]; ok && base == "" {
+			base = DefaultTheme
+		}
+
+		colors := map[string]string{}
+		if base != "" {
+			b, ok := resolved[base]
+			if !ok {
+				errs[theme.Name] = fmt.Errorf("unknown base theme %s for theme %s", base, theme.Name)
+				continue
+			}
+			for element, color := range b.Colors {
+				colors[element] = color
+			}
+		}
+		for element, color := range theme.Colors {
+			colors[element] = color
+		}
+		resolved[theme.Name] = Theme{Name: theme.Name, Colors: colors}
+		delete(errs, theme.Name)
+	}
+	return resolved, errs
+}
@@ -0,0 +1,67 @@
+package filesystem
+
+import "testing"
+
+func TestThemePresets(t *testing.T) {
+	elements := NewThemePresets()[0].Colors
+	for _, preset := range NewThemePresets() {
+		theme, err := Configuration{Theme: preset.Name}.ResolveTheme()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for element := range elements {
+			if theme.Color(element) == "" {
+				t.Errorf("theme %s has no %s color", preset.Name, element)
+			}
+		}
+	}
+}
+
+func TestUserTheme(t *testing.T) {
+	config := Configuration{
+		Theme: "stage",
+		Themes: []Theme{
+			{Name: "stage", Base: "monochrome", Colors: map[string]string{"cursor": "#ff0000"}},
+			{Name: "loop", Base: "loop"},
+			{Name: "default", Colors: map[string]string{"bang": "1"}},
+			{Name: "monochrome", Base: "monochrome", Colors: map[string]string{"grid": "3"}},
+			{Name: "late", Base: "later"},
+			{Name: "later"},
+		},
+	}
+	theme, err := config.ResolveTheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Color("cursor") != "#ff0000" || theme.Color("bang") != "7" || theme.Color("grid_light") != "15" {
+		t.Fatalf("unexpected theme colors: %v", theme.Colors)
+	}
+
+	// A user theme extends the built-in theme of the same name.
+	for _, tt := range []struct{ name, element, color string }{
+		{"default", "bang", "1"},
+		{"default", "cursor", NewThemePresets()[0].Color("cursor")},
+		{"monochrome", "grid", "3"},
+		{"monochrome", "bang", "7"},
+	} {
+		config.Theme = tt.name
+		theme, err := config.ResolveTheme()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if theme.Color(tt.element) != tt.color {
+			t.Fatalf("theme %s: got %s color %q, want %q", tt.name, tt.element, theme.Color(tt.element), tt.color)
+		}
+	}
+
+	for _, name := range []string{"unknown", "loop", "late"} {
+		config.Theme = name
+		theme, err := config.ResolveTheme()
+		if err == nil {
+			t.Fatalf("expected an error for theme %s", name)
+		}
+		if theme.Name != DefaultTheme {
+			t.Fatalf("expected default theme fallback, got %s", theme.Name)
+		}
+	}
+}
@@ -4,10 +4,12 @@ import (
 	_ "embed"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
 
+	"signls/core/command"
 	"signls/core/field"
 	"signls/filesystem"
 	"signls/midi"
@@ -22,7 +24,12 @@ var AppVersion string
 func main() {
 	configFile := flag.String("config", "config.json", "config file to load or create")
 	bankFile := flag.String("bank", "default.json", "bank file to store grids")
+	patternsDir := flag.String("patterns", "patterns", "directory to export and import grid patterns")
 	keyboard := flag.String("keyboard", "", "keyboard layout (qwerty, qwerty-mac, azerty, azerty-mac)")
+	exportText := flag.String("export-text", "", "write a bank grid as text to a file (- for stdout) and exit")
+	importText := flag.String("import-text", "", "read a text grid from a file (- for stdin) into the bank and exit")
+	run := flag.String("run", "", "run commands separated by semicolons on a bank grid and exit")
+	slot := flag.Int("slot", 0, "bank slot (1-32) for text export, import and commands, defaults to the active grid")
 	version := flag.Bool("version", false, "print current version")
 	debug := flag.Bool("debug", false, "enable debug mode")
 	flag.Parse()
@@ -32,7 +39,26 @@ func main() {
 		os.Exit(0)
 	}
 
+	// The configuration registers the custom scales used by text grids
+	// and commands too.
 	config := filesystem.NewConfiguration(*configFile, strings.TrimSuffix(AppVersion, "\n"), *keyboard)
+	bank := filesystem.New(*bankFile)
+	if *exportText != "" || *importText != "" {
+		if err := runText(bank, *slot, *exportText, *importText); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+	if *run != "" {
+		if err := runCommands(bank, *slot, *run); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	// Backups are rotated when starting the ui only, not for one-shot
+	// commands.
+	bank.Backup()
 
 	midi, err := midi.New()
 	if err != nil {
@@ -48,11 +74,68 @@ func main() {
 		defer f.Close()
 	}
 
-	bank := filesystem.New(*bankFile)
-	grid := field.NewFromBank(bank.Active, bank.ActiveGrid(), midi)
+	grid := field.NewFromBank(bank, midi)
 
-	p := tea.NewProgram(ui.New(config, grid, bank))
+	p := tea.NewProgram(ui.New(config, grid, bank, *patternsDir), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// runText exports or imports a bank grid with the text format.
+func runText(bank *filesystem.Bank, slot int, exportFile, importFile string) error {
+	if err := bank.Err(); err != nil {
+		return err
+	}
+	index := bank.Active
+	if slot != 0 {
+		index = slot - 1
+	}
+	if index < 0 || index >= len(bank.Grids) {
+		return fmt.Errorf("invalid bank slot %d", slot)
+	}
+
+	if exportFile != "" {
+		text, err := filesystem.EncodeText(bank.Grid(index))
+		if err != nil {
+			return err
+		}
+		if exportFile == "-" {
+			_, err = fmt.Print(text)
+			return err
+		}
+		return os.WriteFile(exportFile, []byte(text), 0o644)
+	}
+
+	var content []byte
+	var err error
+	if importFile == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(importFile)
+	}
+	if err != nil {
+		return err
+	}
+	grid, err := filesystem.DecodeText(string(content))
+	if err != nil {
+		return err
+	}
+	bank.Save(index, grid)
+	return bank.Err()
+}
+
+// runCommands runs prompt commands on a bank grid, without midi output.
+func runCommands(bank *filesystem.Bank, slot int, script string) error {
+	if err := bank.Err(); err != nil {
+		return err
+	}
+	grid := field.NewFromBank(bank, &midi.Mock{})
+	if slot != 0 {
+		if slot < 1 || slot > len(bank.Grids) {
+			return fmt.Errorf("invalid bank slot %d", slot)
+		}
+		grid.Load(slot-1, bank.Grid(slot-1))
+	}
+	return command.RunScript(command.NewEnv(grid, bank), script)
+}
@@ -28,12 +28,15 @@ type Midi interface {
 	Devices() gomidi.OutPorts
 	NoteOn(device int, channel uint8, note uint8, velocity uint8)
 	NoteOff(device int, channel uint8, note uint8)
+	NoteOffVelocity(device int, channel uint8, note uint8, velocity uint8)
 	Silence(device int, channel uint8)
 	SilenceAll()
 	ControlChange(device int, channel, controller, value uint8)
 	ProgramChange(device int, channel uint8, value uint8)
 	Pitchbend(device int, channel uint8, value int16)
 	AfterTouch(device int, channel uint8, value uint8)
+	PolyAfterTouch(device int, channel uint8, note uint8, value uint8)
+	SysEx(device int, data []byte)
 	SendClock(device int)
 	TransportStart(device int)
 	TransportStop(device int)
@@ -178,6 +181,12 @@ func (m *midi) NoteOff(device int, channel uint8, note uint8) {
 	m.outputs[device] <- gomidi.NoteOff(channel, note)
 }
 
+// NoteOffVelocity sends a Note Off midi message with a release velocity to
+// the active device.
+func (m *midi) NoteOffVelocity(device int, channel uint8, note uint8, velocity uint8) {
+	m.outputs[device] <- gomidi.NoteOffVelocity(channel, note, velocity)
+}
+
 // Silence sends a note off message for every running note on given channel.
 func (m *midi) Silence(device int, channel uint8) {
 	for _, msg := range gomidi.SilenceChannel(int8(channel)) {
@@ -214,6 +223,18 @@ func (m *midi) AfterTouch(device int, channel uint8, value uint8) {
 	m.outputs[device] <- gomidi.AfterTouch(channel, value)
 }
 
+// PolyAfterTouch sends a Polyphonic After Touch messages for a given note
+// to the active device.
+func (m *midi) PolyAfterTouch(device int, channel uint8, note uint8, value uint8) {
+	m.outputs[device] <- gomidi.PolyAfterTouch(channel, note, value)
+}
+
+// SysEx sends a System Exclusive message to the active device. Only the
+// inner bytes must be given, start and end bytes are added.
+func (m *midi) SysEx(device int, data []byte) {
+	m.outputs[device] <- gomidi.SysEx(data)
+}
+
 // SendClock sends a Clock midi meessage to the active device.
 func (m *midi) SendClock(device int) {
 	m.outputs[device] <- gomidi.TimingClock()
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"signls/core/command"
+	"signls/ui/param"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// focusCommand opens the command prompt.
+func (m mainModel) focusCommand() mainModel {
+	m.command = true
+	m.completions = nil
+	m.input.CharLimit = inputCharLimit
+	m.input.Focus()
+	m.input.Reset()
+	return m
+}
+
+// completeCommand completes the last word typed in the command prompt.
+func (m mainModel) completeCommand() mainModel {
+	line, completions := command.Complete(m.commandEnv(), m.input.Value())
+	m.input.SetValue(line)
+	m.input.CursorEnd()
+	m.completions = completions
+	return m
+}
+
+// commandEnv returns the command environment at the cursor.
+func (m mainModel) commandEnv() *command.Env {
+	env := command.NewEnv(m.grid, m.bank)
+	env.X, env.Y = m.cursorX, m.cursorY
+	env.EndX, env.EndY = m.selectionX, m.selectionY
+	env.Query = m.query
+	return env
+}
+
+// runCommand runs a command line typed in the prompt.
+func (m mainModel) runCommand(line string) (tea.Model, tea.Cmd) {
+	m.command = false
+	m.completions = nil
+	env := m.commandEnv()
+	if err := command.Run(env, line); err != nil {
+		m.fileErr = fmt.Errorf("cannot run %s: %w", strings.TrimSpace(line), err)
+		return m, nil
+	}
+	m.fileErr = nil
+
+	m.cursorX, m.cursorY = env.X, env.Y
+	m.selectionX, m.selectionY = env.EndX, env.EndY
+	m.query = env.Query
+	m.sized = m.sized || env.Resized
+	m.params = param.NewParamsForNodes(m.grid, m.selectedEmitters())
+	if len(m.params) < m.paramPage+1 {
+		m.paramPage = 0
+	}
+	if len(m.params) > 0 && len(m.activeParamPage()) < m.param+1 {
+		m.param = 0
+	}
+	m.viewport.Update(m.cursorX, m.cursorY, m.grid.Width, m.grid.Height)
+
+	if env.Load >= 0 {
+		m.selectedGrid = env.Load
+		return m.switchGrid()
+	}
+	return m, tea.Batch(save(m), tea.WindowSize())
+}
+
+// commandHint returns the completion candidates, or the usage of the typed
+// command.
+func (m mainModel) commandHint() string {
+	if len(m.completions) > 0 {
+		return strings.Join(m.completions, "  ")
+	}
+	fields := strings.Fields(m.input.Value())
+	for _, c := range command.Commands() {
+		if len(fields) > 0 && c.Name == fields[0] {
+			return fmt.Sprintf("%s: %s", c.Usage(), c.Help)
+		}
+	}
+	names := []string{}
+	for _, c := range command.Commands() {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, "  ")
+}
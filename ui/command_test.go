@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"signls/core/field"
+	"signls/filesystem"
+	"signls/midi"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestResizeCommand(t *testing.T) {
+	dir := t.TempDir()
+	bank := filesystem.New(filepath.Join(dir, "bank.json"))
+	grid := field.NewFromBank(bank, &midi.Mock{})
+	config := filesystem.NewConfiguration(filepath.Join(dir, "config.json"), "", "")
+	var m tea.Model = New(config, grid, bank, dir)
+
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	m, _ = m.(mainModel).runCommand("resize 48 32")
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	if grid.Width != 48 || grid.Height != 32 {
+		t.Fatalf("got a %dx%d grid, want 48x32", grid.Width, grid.Height)
+	}
+}
+
+func TestEditInputDot(t *testing.T) {
+	dir := t.TempDir()
+	bank := filesystem.New(filepath.Join(dir, "bank.json"))
+	grid := field.NewFromBank(bank, &midi.Mock{})
+	config := filesystem.NewConfiguration(filepath.Join(dir, "config.json"), "", "qwerty")
+	m := New(config, grid, bank, dir).(mainModel)
+	m.input.Focus()
+
+	// The edit input key types a dot in dotted lengths.
+	var model tea.Model = m
+	for _, r := range "1|8." {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m = model.(mainModel)
+	if !m.input.Focused() || m.input.Value() != "1|8." {
+		t.Fatalf("got input %q, focused %v", m.input.Value(), m.input.Focused())
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"signls/core/common"
 	"signls/filesystem"
@@ -12,59 +13,48 @@ import (
 )
 
 const (
-	maxGrids     = 32
-	gridsPerLine = 16
+	maxGrids        = 32
+	gridsPerLine    = 16
+	patternsPerPage = 5
 )
 
 var (
-	pagesArrows = [][]string{
-		{
-			"",
-			"\u23F7",
-		},
-		{
-			"\u23F6",
-			"\u23F7",
-		},
-		{
-			"\u23F6",
-			"",
-		},
-	}
 	controlStyle = lipgloss.NewStyle().
 			MarginTop(1).
 			MarginLeft(2)
 	cellStyle = lipgloss.NewStyle().
 			MarginRight(2)
-	activeCellStyle = cellStyle.
-			Foreground(lipgloss.Color("190"))
-	bankStyle = lipgloss.NewStyle().
-			MarginRight(1).
-			Background(lipgloss.Color("79")).
-			Foreground(lipgloss.Color("0"))
-	bankStyleOdd = lipgloss.NewStyle().
-			MarginRight(1).
-			Background(lipgloss.Color("85")).
-			Foreground(lipgloss.Color("0"))
-	activeBankStyle = lipgloss.NewStyle().
-			MarginRight(1).
-			Background(lipgloss.Color("15")).
-			Foreground(lipgloss.Color("0"))
 )
 
 func (m mainModel) renderControl() string {
+	if m.recovery != "" {
+		return controlStyle.Render(m.recoveryPrompt())
+	}
+	if len(m.reload) > 0 {
+		return controlStyle.Render(m.reloadPrompt())
+	}
 	if m.mode == BANK {
 		return controlStyle.Render(m.bankSelection())
 	}
+	if m.mode == PATTERN {
+		return controlStyle.Render(m.patternSelection())
+	}
+	if m.mode == MIXER {
+		return controlStyle.Render(m.mixerSelection())
+	}
 
 	var pane string
-	if m.mode == EDIT && m.input.Focused() {
+	if m.command && m.input.Focused() {
+		pane = fmt.Sprintf("command %s", m.input.View())
+	} else if m.mode == MOVE && m.input.Focused() {
+		pane = fmt.Sprintf("select %s", m.input.View())
+	} else if m.editingParams() && m.input.Focused() {
 		pane = fmt.Sprintf(
 			"%s %s",
 			m.activeParam().Name(),
 			m.input.View(),
 		)
-	} else if m.mode == EDIT || m.mode == CONFIG {
+	} else if m.editingParams() {
 		pane = m.paramEdit()
 	} else {
 		pane = m.gridInfo()
@@ -73,24 +63,73 @@ func (m mainModel) renderControl() string {
 	return controlStyle.Render(
 		lipgloss.JoinHorizontal(
 			lipgloss.Left,
-			lipgloss.JoinVertical(
-				lipgloss.Left,
-				cellStyle.Width(9).Render(m.selectedNodeName()),
-				cellStyle.Render(m.modeName()),
-			),
+			m.controlLabels(),
 			pane,
 		),
 	)
 }
 
+// controlLabels returns the selected node and mode names, shown on the
+// left of the controls.
+func (m mainModel) controlLabels() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		cellStyle.Width(9).Render(m.selectedNodeName()),
+		cellStyle.Render(m.modeName()),
+	)
+}
+
+func (m mainModel) recoveryPrompt() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		fmt.Sprintf("bank cannot be read, recover from %s?", m.recovery),
+		fmt.Sprintf(
+			"%s recover   %s ignore",
+			m.keymap.EditNode.Help().Key,
+			m.keymap.Cancel.Help().Key,
+		),
+	)
+}
+
+func (m mainModel) reloadPrompt() string {
+	grids := make([]string, len(m.reload))
+	for i, nb := range m.reload {
+		grids[i] = fmt.Sprintf("%d", nb+1)
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		fmt.Sprintf("bank changed on disk, reload playing grid %s?", strings.Join(grids, ", ")),
+		fmt.Sprintf(
+			"%s reload   %s keep",
+			m.keymap.EditNode.Help().Key,
+			m.keymap.Cancel.Help().Key,
+		),
+	)
+}
+
 func (m mainModel) bankSelection() string {
 	banks := make([]string, maxGrids)
+	queued := map[int]bool{}
+	for _, l := range m.grid.Layers() {
+		queued[l.Queued()] = true
+	}
 	for i, g := range m.bank.Grids[:maxGrids] {
 		label := bankGridLabel(i, g)
+		layer := m.grid.Layer(i)
 		if i == m.selectedGrid {
 			banks[i] = cursorStyle.MarginRight(1).Render(label)
+		} else if queued[i] {
+			banks[i] = queuedBankStyle.Render(label)
 		} else if i == m.bank.Active {
 			banks[i] = activeBankStyle.Render(label)
+		} else if layer != nil && layer.Solo {
+			banks[i] = soloLayerBankStyle.Render(label)
+		} else if layer != nil && layer.Muted {
+			banks[i] = mutedLayerBankStyle.Render(label)
+		} else if layer != nil {
+			banks[i] = layerBankStyle.Render(label)
+		} else if color, ok := tagColor(g); ok {
+			banks[i] = bankStyle.Background(color).Render(label)
 		} else if (i < gridsPerLine && i%2 == 0) || (i >= gridsPerLine && i%2 == 1) {
 			banks[i] = bankStyle.Render(label)
 		} else {
@@ -109,12 +148,85 @@ func (m mainModel) bankSelection() string {
 			banks[gridsPerLine:maxGrids]...,
 		),
 	)
+	if m.input.Focused() {
+		pane = lipgloss.JoinVertical(
+			lipgloss.Left,
+			fmt.Sprintf("%s %s", m.bankInputName(), m.input.View()),
+			gridTitle(m.bank.Grid(m.selectedGrid)),
+		)
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		m.bankLabels(),
+		pane,
+	)
+}
+
+// bankLabels returns the active grid and mode names, shown on the left of
+// the bank.
+func (m mainModel) bankLabels() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		activeBankStyle.MarginRight(9).Render(bankGridLabel(m.bank.Active, m.bank.ActiveGrid())),
+		cellStyle.Render(m.modeName()),
+	)
+}
+
+func (m mainModel) bankInputName() string {
+	switch m.bankInput {
+	case bankInputNotes:
+		return fmt.Sprintf("grid %d notes", m.selectedGrid+1)
+	case bankInputSearch:
+		return "search"
+	default:
+		return fmt.Sprintf("grid %d name", m.selectedGrid+1)
+	}
+}
+
+// gridTitle returns the name of a bank grid, in its tag color.
+func gridTitle(g filesystem.Grid) string {
+	if g.Name == "" {
+		return ""
+	}
+	style := lipgloss.NewStyle().MarginRight(1)
+	if color, ok := tagColor(g); ok {
+		style = style.Foreground(color)
+	}
+	return style.Render(g.Name)
+}
+
+func (m mainModel) patternSelection() string {
+	var pane string
+	if m.input.Focused() {
+		pane = fmt.Sprintf("export %s", m.input.View())
+	} else if len(m.patterns) == 0 {
+		pane = fmt.Sprintf("no pattern in %s", m.patternsDir)
+	} else {
+		page := m.selectedPattern / patternsPerPage * patternsPerPage
+		patterns := []string{
+			cellStyle.Render(
+				lipgloss.JoinVertical(
+					lipgloss.Left,
+					pageArrows(page/patternsPerPage, (len(m.patterns)-1)/patternsPerPage+1)...,
+				),
+			),
+		}
+		for i := page; i < page+patternsPerPage && i < len(m.patterns); i++ {
+			style := cellStyle
+			if i == m.selectedPattern {
+				style = activeCellStyle
+			}
+			patterns = append(patterns, style.Render(m.patterns[i]))
+		}
+		pane = lipgloss.JoinHorizontal(lipgloss.Left, patterns...)
+	}
 
 	return lipgloss.JoinHorizontal(
 		lipgloss.Left,
 		lipgloss.JoinVertical(
 			lipgloss.Left,
-			activeBankStyle.MarginRight(9).Render(bankGridLabel(m.bank.Active, m.bank.ActiveGrid())),
+			cellStyle.Width(9).Render(m.patternsDir),
 			cellStyle.Render(m.modeName()),
 		),
 		pane,
@@ -144,28 +256,61 @@ func (m mainModel) gridInfo() string {
 		lipgloss.JoinVertical(
 			lipgloss.Left,
 			fmt.Sprintf(
-				"%s%s",
+				"%s%s%s",
 				activeBankStyle.Render(bankGridLabel(m.bank.Active, m.bank.ActiveGrid())),
+				gridTitle(m.bank.ActiveGrid()),
 				m.bank.Filename(),
 			),
+			m.layerInfo(),
 		),
 	)
 }
 
+// layerInfo returns the edited layer position and state when several
+// grids are playing.
+func (m mainModel) layerInfo() string {
+	layers := m.grid.Layers()
+	if len(layers) == 1 {
+		return ""
+	}
+	info := ""
+	for i, l := range layers {
+		if l == m.grid {
+			info = fmt.Sprintf("layer %d/%d", i+1, len(layers))
+		}
+	}
+	if m.grid.Solo {
+		info += " solo"
+	} else if !m.grid.Audible() {
+		info += " muted"
+	}
+	return info
+}
+
 func (m mainModel) paramEdit() string {
-	var params []string
+	return lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		append([]string{m.paramArrows()}, m.paramCells()...)...,
+	)
+}
 
-	if len(m.params) > 1 {
-		params = []string{
-			cellStyle.Render(
-				lipgloss.JoinVertical(
-					lipgloss.Left,
-					pagesArrows[m.paramPage]...,
-				),
-			),
-		}
+// paramArrows returns the parameter page arrows, when there are several
+// pages.
+func (m mainModel) paramArrows() string {
+	if len(m.params) <= 1 {
+		return ""
 	}
+	return cellStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			pageArrows(m.paramPage, len(m.params))...,
+		),
+	)
+}
 
+// paramCells returns the parameters of the active page with their values.
+func (m mainModel) paramCells() []string {
+	var params []string
 	for k, p := range m.activeParamPage() {
 		style := cellStyle
 		if k == m.param {
@@ -180,10 +325,20 @@ func (m mainModel) paramEdit() string {
 			),
 		)
 	}
-	return lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		params...,
-	)
+	return params
+}
+
+// pageArrows returns the arrows showing if there are parameter
+// pages above or below the current one.
+func pageArrows(page, pages int) []string {
+	arrows := []string{"", ""}
+	if page > 0 {
+		arrows[0] = "\u23F6"
+	}
+	if page < pages-1 {
+		arrows[1] = "\u23F7"
+	}
+	return arrows
 }
 
 func (m mainModel) tempoSymbol() string {
@@ -208,6 +363,12 @@ func (m mainModel) modeName() string {
 		return "edit"
 	case CONFIG:
 		return "config"
+	case SONG:
+		return "song"
+	case PATTERN:
+		return "patterns"
+	case MIXER:
+		return "mixer"
 	default:
 		return "move"
 	}
@@ -218,6 +379,9 @@ func (m mainModel) selectedNode() common.Node {
 }
 
 func (m mainModel) selectedEmitters() []common.Node {
+	if m.query != nil {
+		return m.grid.Select(*m.query)
+	}
 	nodes := []common.Node{}
 	for y := m.cursorY; y <= m.selectionY; y++ {
 		for x := m.cursorX; x <= m.selectionX; x++ {
@@ -243,7 +407,8 @@ func (m mainModel) selectedNodeName() string {
 		lipgloss.Left,
 		emitterStyle.
 			MarginRight(1).
-			Background(lipgloss.Color(nodes[0].Color())).
+			Background(nodeColor(nodes[0])).
+			Foreground(nodeTextColor(nodes[0])).
 			Render(util.Normalize(nodes[0].Symbol())),
 		nodes[0].Name(),
 	)
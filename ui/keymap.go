@@ -26,6 +26,7 @@ type keyMap struct {
 	EditLeft  key.Binding
 
 	EditInput key.Binding
+	Command   key.Binding
 
 	Bank key.Binding
 
@@ -39,9 +40,10 @@ type keyMap struct {
 	AddZone   key.Binding
 	AddHole   key.Binding
 
-	Copy  key.Binding
-	Cut   key.Binding
-	Paste key.Binding
+	Copy     key.Binding
+	Cut      key.Binding
+	Paste    key.Binding
+	CopyText key.Binding
 
 	EditNode    key.Binding
 	RemoveNode  key.Binding
@@ -50,6 +52,15 @@ type keyMap struct {
 	MuteNode    key.Binding
 	MuteAllNode key.Binding
 
+	SelectNodes key.Binding
+
+	Layer key.Binding
+	Solo  key.Binding
+
+	GridColor  key.Binding
+	GridNotes  key.Binding
+	SearchGrid key.Binding
+
 	RootNoteUp   key.Binding
 	RootNoteDown key.Binding
 	ScaleUp      key.Binding
@@ -58,6 +69,10 @@ type keyMap struct {
 	TempoDown    key.Binding
 
 	Configuration   key.Binding
+	Song            key.Binding
+	Patterns        key.Binding
+	NoteLog         key.Binding
+	Mixer           key.Binding
 	FitGridToWindow key.Binding
 
 	Cancel key.Binding
@@ -76,8 +91,8 @@ func (k keyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Bank, k.AddBang, k.AddEuclid, k.AddPass, k.AddSpread, k.AddCycle, k.AddDice, k.AddToll, k.AddZone, k.AddHole, k.RootNoteUp, k.RootNoteDown, k.ScaleUp, k.ScaleDown, k.Cancel, k.Configuration, k.FitGridToWindow, k.Help, k.Quit},
-		{k.Play, k.EditNode, k.RemoveNode, k.TriggerNode, k.MuteNode, k.MuteAllNode, k.Copy, k.Cut, k.Paste, k.Up, k.Right, k.Down, k.Left, k.SelectionUp, k.SelectionRight, k.SelectionDown, k.SelectionLeft, k.EditUp, k.EditDown, k.EditRight, k.EditLeft, k.EditInput},
+		{k.Bank, k.AddBang, k.AddEuclid, k.AddPass, k.AddSpread, k.AddCycle, k.AddDice, k.AddToll, k.AddZone, k.AddHole, k.RootNoteUp, k.RootNoteDown, k.ScaleUp, k.ScaleDown, k.Cancel, k.Configuration, k.Song, k.Patterns, k.NoteLog, k.Mixer, k.FitGridToWindow, k.Help, k.Quit},
+		{k.Play, k.EditNode, k.RemoveNode, k.TriggerNode, k.MuteNode, k.MuteAllNode, k.SelectNodes, k.Layer, k.Solo, k.GridColor, k.GridNotes, k.SearchGrid, k.Copy, k.Cut, k.Paste, k.CopyText, k.Up, k.Right, k.Down, k.Left, k.SelectionUp, k.SelectionRight, k.SelectionDown, k.SelectionLeft, k.EditUp, k.EditDown, k.EditRight, k.EditLeft, k.EditInput, k.Command},
 	}
 }
 
@@ -180,7 +195,11 @@ func newKeyMap(keys filesystem.KeyMap) keyMap {
 		),
 		EditInput: key.NewBinding(
 			key.WithKeys(keys.EditInput),
-			key.WithHelp(keys.EditInput, "modify parameter"),
+			key.WithHelp(keys.EditInput, "modify parameter | name bank grid"),
+		),
+		Command: key.NewBinding(
+			key.WithKeys(keys.Command),
+			key.WithHelp(keys.Command, "command prompt (in move mode)"),
 		),
 		Bank: key.NewBinding(
 			key.WithKeys(keys.Bank),
@@ -234,6 +253,10 @@ func newKeyMap(keys filesystem.KeyMap) keyMap {
 			key.WithKeys(keys.Paste),
 			key.WithHelp(keys.Paste, "paste node | bank"),
 		),
+		CopyText: key.NewBinding(
+			key.WithKeys(keys.CopyText),
+			key.WithHelp(keys.CopyText, "copy grid as text"),
+		),
 		EditNode: key.NewBinding(
 			key.WithKeys(keys.EditNode),
 			key.WithHelp(keys.EditNode, "edit selected nodes parameters"),
@@ -254,6 +277,30 @@ func newKeyMap(keys filesystem.KeyMap) keyMap {
 			key.WithKeys(keys.MuteAllNode),
 			key.WithHelp(keys.MuteAllNode, "mute/unmute all selected nodes"),
 		),
+		SelectNodes: key.NewBinding(
+			key.WithKeys(keys.SelectNodes),
+			key.WithHelp(keys.SelectNodes, "select nodes by type or property"),
+		),
+		Layer: key.NewBinding(
+			key.WithKeys(keys.Layer),
+			key.WithHelp(keys.Layer, "next layer | toggle bank layer"),
+		),
+		Solo: key.NewBinding(
+			key.WithKeys(keys.Solo),
+			key.WithHelp(keys.Solo, "toggle selected nodes | bank layer solo"),
+		),
+		GridColor: key.NewBinding(
+			key.WithKeys(keys.GridColor),
+			key.WithHelp(keys.GridColor, "change bank grid color"),
+		),
+		GridNotes: key.NewBinding(
+			key.WithKeys(keys.GridNotes),
+			key.WithHelp(keys.GridNotes, "edit bank grid notes"),
+		),
+		SearchGrid: key.NewBinding(
+			key.WithKeys(keys.SearchGrid),
+			key.WithHelp(keys.SearchGrid, "search bank grid by name"),
+		),
 		RootNoteUp: key.NewBinding(
 			key.WithKeys(keys.RootNoteUp),
 			key.WithHelp(keys.RootNoteUp, "increase root note"),
@@ -282,6 +329,22 @@ func newKeyMap(keys filesystem.KeyMap) keyMap {
 			key.WithKeys(keys.Configuration),
 			key.WithHelp(keys.Configuration, "config"),
 		),
+		Song: key.NewBinding(
+			key.WithKeys(keys.Song),
+			key.WithHelp(keys.Song, "song"),
+		),
+		Patterns: key.NewBinding(
+			key.WithKeys(keys.Patterns),
+			key.WithHelp(keys.Patterns, "patterns"),
+		),
+		NoteLog: key.NewBinding(
+			key.WithKeys(keys.NoteLog),
+			key.WithHelp(keys.NoteLog, "note log | piano roll"),
+		),
+		Mixer: key.NewBinding(
+			key.WithKeys(keys.Mixer),
+			key.WithHelp(keys.Mixer, "channel and device mixer"),
+		),
 		FitGridToWindow: key.NewBinding(
 			key.WithKeys(keys.FitGridToWindow),
 			key.WithHelp(keys.FitGridToWindow, "fit grid to window"),
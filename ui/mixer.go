@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+
+	"signls/core/music"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mixerChannels is the number of midi channels shown in the mixer.
+const mixerChannels = 16
+
+// mixerStrips returns the number of mixer strips: the midi channels
+// followed by the midi devices.
+func (m mainModel) mixerStrips() int {
+	return mixerChannels + len(m.grid.Midi().Devices())
+}
+
+// moveMixerStrip moves the mixer cursor between strips, up and down
+// switching between channels and devices.
+func (m *mainModel) moveMixerStrip(dir string) {
+	devices := m.mixerStrips() - mixerChannels
+	switch dir {
+	case "up":
+		if m.mixerStrip >= mixerChannels {
+			m.mixerStrip = min(m.mixerStrip-mixerChannels, mixerChannels-1)
+		}
+	case "down":
+		if m.mixerStrip < mixerChannels && devices > 0 {
+			m.mixerStrip = mixerChannels + min(m.mixerStrip, devices-1)
+		}
+	case "left":
+		m.mixerStrip--
+	case "right":
+		m.mixerStrip++
+	}
+	m.mixerStrip = max(min(m.mixerStrip, m.mixerStrips()-1), 0)
+}
+
+// toggleMixerStrip mutes or solos the selected channel or device.
+func (m mainModel) toggleMixerStrip(s music.Strip) {
+	if m.mixerStrip < mixerChannels {
+		m.grid.Mixer().ToggleChannel(uint8(m.mixerStrip), s)
+		return
+	}
+	m.grid.Mixer().ToggleDevice(m.mixerStrip-mixerChannels, s)
+}
+
+// clearMixer lets all the nodes play again, or brings back the mutes and
+// solos of the last clear.
+func (m mainModel) clearMixer() {
+	mixer := m.grid.Mixer()
+	if mixer.Active() {
+		mixer.Clear()
+		return
+	}
+	mixer.Restore()
+}
+
+func (m mainModel) mixerSelection() string {
+	mixer := m.grid.Mixer()
+	channels := make([]string, mixerChannels)
+	for i := range channels {
+		channels[i] = m.mixerStripCell(i, fmt.Sprintf("%2d", i+1), mixer.Channel(uint8(i)))
+	}
+	devices := []string{}
+	for i := 0; i < m.mixerStrips()-mixerChannels; i++ {
+		name := fmt.Sprintf("%-*s", deviceNameWidth, truncate(m.grid.Midi().GetDevice(i).Name, deviceNameWidth))
+		devices = append(devices, m.mixerStripCell(mixerChannels+i, name, mixer.Device(i)))
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			cellStyle.Width(9).Render(m.mixerStripName()),
+			cellStyle.Render(m.modeName()),
+		),
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.JoinHorizontal(lipgloss.Left, channels...),
+			lipgloss.JoinHorizontal(lipgloss.Left, devices...),
+		),
+	)
+}
+
+func (m mainModel) mixerStripCell(strip int, label string, s music.Strip) string {
+	switch {
+	case strip == m.mixerStrip:
+		return cursorStyle.MarginRight(1).Render(label)
+	case s == music.StripSolo:
+		return soloLayerBankStyle.Render(label)
+	case s == music.StripMuted:
+		return mutedLayerBankStyle.Render(label)
+	case strip%2 == 0:
+		return bankStyle.Render(label)
+	default:
+		return bankStyleOdd.Render(label)
+	}
+}
+
+// mixerStripName returns the name of the selected strip.
+func (m mainModel) mixerStripName() string {
+	if m.mixerStrip >= mixerChannels {
+		return fmt.Sprintf("dev %d", m.mixerStrip-mixerChannels+1)
+	}
+	return fmt.Sprintf("ch %d", m.mixerStrip+1)
+}
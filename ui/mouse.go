@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"signls/ui/param"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// handleMouse moves the cursor and selects regions in the grid, changes
+// parameters and switches bank grids.
+func (m mainModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action == tea.MouseActionRelease {
+		m.dragging = false
+		return m, nil
+	}
+	if m.help.ShowAll || m.input.Focused() || m.recovery != "" || len(m.reload) > 0 {
+		return m, nil
+	}
+
+	switch {
+	case msg.Y < m.viewport.Height:
+		return m.handleGridMouse(msg), nil
+	case m.mode == BANK:
+		return m.handleBankMouse(msg)
+	case m.editingParams():
+		return m.handleParamMouse(msg)
+	}
+	return m, nil
+}
+
+// handleGridMouse moves the cursor on click and selects the region between
+// the clicked and the current cell on drag.
+func (m mainModel) handleGridMouse(msg tea.MouseMsg) mainModel {
+	if m.mode != MOVE && m.mode != EDIT {
+		return m
+	}
+	x, y := m.viewport.offsetX+msg.X/2, m.viewport.offsetY+msg.Y
+	if msg.X/2 >= m.viewport.Width || x >= m.grid.Width || y >= m.grid.Height {
+		return m
+	}
+
+	switch {
+	case msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft:
+		m.query = nil
+		m.dragging = true
+		m.dragX, m.dragY = x, y
+		m.cursorX, m.cursorY = x, y
+		m.selectionX, m.selectionY = x, y
+	case msg.Action == tea.MouseActionMotion && m.dragging:
+		m.cursorX, m.selectionX = min(m.dragX, x), max(m.dragX, x)
+		m.cursorY, m.selectionY = min(m.dragY, y), max(m.dragY, y)
+	default:
+		return m
+	}
+
+	m.blink = true
+	m.params = param.NewParamsForNodes(m.grid, m.selectedEmitters())
+	if m.mode == EDIT && len(m.selectedEmitters()) == 0 {
+		m.mode = MOVE
+	}
+	if len(m.params) < m.paramPage+1 {
+		m.paramPage = 0
+	}
+	if len(m.activeParamPage()) < m.param+1 {
+		m.param = 0
+	}
+	return m
+}
+
+// handleBankMouse switches to the clicked bank grid.
+func (m mainModel) handleBankMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return m, nil
+	}
+	row := msg.Y - m.viewport.Height - controlStyle.GetMarginTop()
+	x := msg.X - controlStyle.GetMarginLeft() - lipgloss.Width(m.bankLabels())
+	cellWidth := lipgloss.Width(bankStyle.Render(bankGridLabel(0, m.bank.Grid(0))))
+	if row < 0 || row >= maxGrids/gridsPerLine || x < 0 || x >= gridsPerLine*cellWidth {
+		return m, nil
+	}
+	m.selectedGrid = row*gridsPerLine + x/cellWidth
+	return m.switchGrid()
+}
+
+// handleParamMouse selects the clicked parameter, and changes the hovered
+// parameter value with the mouse wheel.
+func (m mainModel) handleParamMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	row := msg.Y - m.viewport.Height - controlStyle.GetMarginTop()
+	x := msg.X - controlStyle.GetMarginLeft() - lipgloss.Width(m.controlLabels()) - lipgloss.Width(m.paramArrows())
+	if row < 0 || row > 1 || x < 0 {
+		return m, nil
+	}
+
+	for i, cell := range m.paramCells() {
+		if x >= lipgloss.Width(cell) {
+			x -= lipgloss.Width(cell)
+			continue
+		}
+		switch msg.Button {
+		case tea.MouseButtonLeft:
+			m.param = i
+			return m, nil
+		case tea.MouseButtonWheelUp:
+			m.param = i
+			m.handleParamEdit("up")
+			return m, save(m)
+		case tea.MouseButtonWheelDown:
+			m.param = i
+			m.handleParamEdit("down")
+			return m, save(m)
+		}
+		return m, nil
+	}
+	return m, nil
+}
@@ -8,30 +8,6 @@ import (
 	"signls/core/node"
 	"signls/ui/param"
 	"signls/ui/util"
-
-	"github.com/charmbracelet/lipgloss"
-)
-
-var (
-	gridStyle = lipgloss.NewStyle().
-			Background(lipgloss.AdaptiveColor{Light: "254", Dark: "234"})
-	cursorStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("190")).
-			Foreground(lipgloss.Color("0"))
-	teleportDestinationStyle = lipgloss.NewStyle().
-					Background(lipgloss.Color("160")).
-					Foreground(lipgloss.Color("15"))
-	selectionStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("238")).
-			Foreground(lipgloss.Color("244"))
-	emitterStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("15"))
-	mutedEmitterStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("247")).
-				Foreground(lipgloss.Color("236"))
-	activeEmitterStyle = lipgloss.NewStyle().
-				Background(lipgloss.AdaptiveColor{Light: "0", Dark: "15"}).
-				Foreground(lipgloss.AdaptiveColor{Light: "15", Dark: "0"})
 )
 
 func (m mainModel) inSelectionRange(x, y int) bool {
@@ -41,6 +17,17 @@ func (m mainModel) inSelectionRange(x, y int) bool {
 		y <= m.selectionY
 }
 
+// inQuery returns true if a node is selected by the node query.
+func (m mainModel) inQuery(n common.Node) bool {
+	return m.query != nil && m.mode != BANK && m.query.Match(n)
+}
+
+// audible returns true if a node is not silenced by the mixer.
+func (m mainModel) audible(a music.Audible) bool {
+	note := a.Note()
+	return m.grid.Mixer().Audible(note, note.Device.Get(), note.Channel.Value())
+}
+
 func (m mainModel) renderNode(n common.Node, x, y int) string {
 	// render cursor
 	isCursor := false
@@ -90,17 +77,24 @@ func (m mainModel) renderNode(n common.Node, x, y int) string {
 			return teleportDestinationStyle.Render(teleportDestinationSymbol)
 		} else if isCursor && m.mode == EDIT && m.blink {
 			return cursorStyle.Render(symbol)
+		} else if m.inQuery(n) && m.blink {
+			return cursorStyle.Render(symbol)
 		} else if n.Activated() && t.Muted() {
 			return activeEmitterStyle.Render(symbol)
 		} else if t.Muted() {
 			return mutedEmitterStyle.Render(symbol)
+		} else if !m.audible(t) {
+			return mutedEmitterStyle.
+				Foreground(nodeColor(n)).
+				Render(symbol)
 		} else if n.Activated() {
 			return activeEmitterStyle.
-				Foreground(lipgloss.Color(n.Color())).
+				Foreground(nodeColor(n)).
 				Render(symbol)
 		} else {
 			return emitterStyle.
-				Background(lipgloss.Color(n.Color())).
+				Background(nodeColor(n)).
+				Foreground(nodeTextColor(n)).
 				Render(symbol)
 		}
 	case *node.HoleEmitter:
@@ -110,13 +104,16 @@ func (m mainModel) renderNode(n common.Node, x, y int) string {
 			return cursorStyle.Render(symbol)
 		} else if isCursor && m.mode == EDIT && m.blink {
 			return cursorStyle.Render(symbol)
+		} else if m.inQuery(n) && m.blink {
+			return cursorStyle.Render(symbol)
 		} else if n.Activated() {
 			return activeEmitterStyle.
-				Foreground(lipgloss.Color(n.Color())).
+				Foreground(nodeColor(n)).
 				Render(symbol)
 		} else {
 			return emitterStyle.
-				Background(lipgloss.Color(n.Color())).
+				Background(nodeColor(n)).
+				Foreground(nodeTextColor(n)).
 				Render(symbol)
 		}
 	default:
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"signls/core/common"
+	"signls/core/field"
+	"signls/core/theory"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// notePane is the side pane showing the notes sent while playing.
+type notePane uint8
+
+const (
+	noNotePane notePane = iota
+	noteLogPane
+	pianoRollPane
+)
+
+const (
+	notePaneWidth     = 44
+	pianoRollKeyWidth = 5
+	deviceNameWidth   = 8
+)
+
+var notePaneStyle = lipgloss.NewStyle().
+	MarginLeft(2).
+	Width(notePaneWidth - 2)
+
+// toggleNotePane shows the note log, then the piano roll, then hides the
+// pane.
+func (m mainModel) toggleNotePane() mainModel {
+	m.notePane = (m.notePane + 1) % (pianoRollPane + 1)
+	m.grid.EnableNoteLog(m.notePane != noNotePane)
+	return m
+}
+
+// sidePaneWidth returns the width taken by the note pane.
+func (m mainModel) sidePaneWidth() int {
+	if m.notePane == noNotePane {
+		return 0
+	}
+	return notePaneWidth
+}
+
+func (m mainModel) renderNotePane() string {
+	log := m.grid.NoteLog()
+	if log == nil {
+		return ""
+	}
+	events := log.Events()
+	var pane string
+	switch {
+	case len(events) == 0:
+		pane = m.help.Styles.ShortDesc.Render("no note sent")
+	case m.notePane == pianoRollPane:
+		pane = m.pianoRoll(events)
+	default:
+		pane = m.noteLog(events)
+	}
+	return notePaneStyle.Height(m.viewport.Height).Render(pane)
+}
+
+// noteLog returns the last notes sent, newest last.
+func (m mainModel) noteLog(events []field.NoteEvent) string {
+	rows := max(m.viewport.Height-1, 0)
+	events = events[max(len(events)-rows, 0):]
+	lines := []string{
+		m.help.Styles.ShortDesc.Render("time      x,y   device   ch note vel len"),
+	}
+	for _, e := range events {
+		position := "-"
+		if e.X >= 0 {
+			position = fmt.Sprintf("%d,%d", e.X, e.Y)
+		}
+		length := "…"
+		if !e.Held() {
+			length = fmt.Sprintf("%.1f", float64(e.Length)/float64(common.PulsesPerStep))
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%s %-5s %-8s %2d %-4s %3d %s",
+			e.Time.Format("04:05.000"),
+			position,
+			truncate(e.Device, deviceNameWidth),
+			e.Channel+1,
+			theory.Key(e.Key).Name(),
+			e.Velocity,
+			length,
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pianoRoll returns the notes sent over the last steps, one row per key
+// with the highest key on top.
+func (m mainModel) pianoRoll(events []field.NoteEvent) string {
+	pulsesPerStep := uint64(common.PulsesPerStep)
+	steps := uint64(notePaneWidth - 2 - pianoRollKeyWidth)
+	now := m.grid.Root().Pulse()
+	start := uint64(0)
+	if now >= steps {
+		start = now - steps + 1
+	}
+
+	type span struct {
+		key        uint8
+		start, end uint64
+	}
+	spans := []span{}
+	lowest, highest := uint8(127), uint8(0)
+	for _, e := range events {
+		s := span{key: e.Key, start: e.Pulse / pulsesPerStep, end: now}
+		if !e.Held() {
+			s.end = (e.Pulse + uint64(e.Length)) / pulsesPerStep
+		}
+		// Notes from a previous run start after the current step.
+		if s.start > now || s.end < start {
+			continue
+		}
+		spans = append(spans, s)
+		lowest, highest = min(lowest, s.key), max(highest, s.key)
+	}
+	if len(spans) == 0 {
+		return m.help.Styles.ShortDesc.Render("no recent note")
+	}
+
+	keys := min(int(highest-lowest)+1, m.viewport.Height)
+	rows := make([][]rune, keys)
+	for i := range rows {
+		rows[i] = []rune(strings.Repeat("·", int(steps)))
+	}
+	for _, s := range spans {
+		row := int(highest - s.key)
+		if row >= keys {
+			continue
+		}
+		for step := max(s.start, start); step <= s.end && step < start+steps; step++ {
+			rows[row][step-start] = '█'
+		}
+	}
+
+	lines := make([]string, keys)
+	for i, row := range rows {
+		lines[i] = lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			m.help.Styles.ShortDesc.Width(pianoRollKeyWidth).Render(theory.Key(highest-uint8(i)).Name()),
+			string(row),
+		)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate shortens a text to a maximum number of characters.
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length])
+}
@@ -0,0 +1,70 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/core/field"
+	"signls/core/music"
+)
+
+type BendRange struct {
+	grid *field.Grid
+}
+
+func (b BendRange) Name() string {
+	return "bend"
+}
+
+func (b BendRange) Help() string {
+	return "synth pitch bend range in semitones"
+}
+
+func (b BendRange) Display() string {
+	return fmt.Sprintf("%d", b.grid.Performance.BendRange)
+}
+
+func (b BendRange) Value() int {
+	return b.grid.Performance.BendRange
+}
+
+func (b BendRange) AltValue() int {
+	return 0
+}
+
+func (b BendRange) Up() {
+	b.Set(b.Value() + 1)
+}
+
+func (b BendRange) Down() {
+	b.Set(b.Value() - 1)
+}
+
+func (b BendRange) Left() {}
+
+func (b BendRange) Right() {}
+
+func (b BendRange) AltUp() {}
+
+func (b BendRange) AltDown() {}
+
+func (b BendRange) AltLeft() {}
+
+func (b BendRange) AltRight() {}
+
+func (b BendRange) Set(value int) {
+	if value < 1 || value > music.MaxBendRange {
+		return
+	}
+	b.grid.Performance.BendRange = value
+}
+
+func (b BendRange) SetAlt(value int) {}
+
+func (b BendRange) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	b.Set(value)
+}
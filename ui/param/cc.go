@@ -11,6 +11,10 @@ import (
 	"signls/ui/util"
 )
 
+const (
+	highResValueStep = 128
+)
+
 type CC struct {
 	index int
 	nodes []common.Node
@@ -20,8 +24,14 @@ func (c CC) Name() string {
 	switch c.nodes[0].(music.Audible).Note().Controls[c.index].Type {
 	case music.ControlChangeControlType:
 		return fmt.Sprintf("cc%d", c.nodes[0].(music.Audible).Note().Controls[c.index].Controller)
+	case music.HighResControlChangeControlType:
+		return fmt.Sprintf("hr%d", c.nodes[0].(music.Audible).Note().Controls[c.index].Controller)
+	case music.NRPNControlType:
+		return fmt.Sprintf("n%d", c.nodes[0].(music.Audible).Note().Controls[c.index].Controller)
 	case music.AfterTouchControlType:
 		return "at"
+	case music.PolyAfterTouchControlType:
+		return "pat"
 	case music.PitchBendControlType:
 		return "pb"
 	case music.ProgramChangeControlType:
@@ -34,9 +44,17 @@ func (c CC) Name() string {
 func (c CC) Help() string {
 	switch c.nodes[0].(music.Audible).Note().Controls[c.index].Type {
 	case music.ControlChangeControlType:
-		return strings.ToLower(midi.CC(c.nodes[0].(music.Audible).Note().Controls[c.index].Controller))
+		return strings.ToLower(midi.CC(uint8(c.nodes[0].(music.Audible).Note().Controls[c.index].Controller)))
+	case music.HighResControlChangeControlType:
+		controller := c.nodes[0].(music.Audible).Note().Controls[c.index].Controller
+		return fmt.Sprintf("14-bit cc %d/%d %s", controller, controller+32, strings.ToLower(midi.CC(uint8(controller))))
+	case music.NRPNControlType:
+		controller := c.nodes[0].(music.Audible).Note().Controls[c.index].Controller
+		return fmt.Sprintf("nrpn msb %d lsb %d (#n sets parameter)", controller>>7, controller&0x7f)
 	case music.AfterTouchControlType:
 		return "after touch"
+	case music.PolyAfterTouchControlType:
+		return "poly after touch"
 	case music.PitchBendControlType:
 		return "pitch bend"
 	case music.ProgramChangeControlType:
@@ -71,19 +89,19 @@ func (c CC) AltValue() int {
 }
 
 func (c CC) Up() {
-	c.Set(c.Value() + 1)
+	c.Set(min(c.Value()+c.step(), int(c.nodes[0].(music.Audible).Note().Controls[c.index].Value.Max())))
 }
 
 func (c CC) Down() {
-	c.Set(c.Value() - 1)
+	c.Set(max(c.Value()-c.step(), 0))
 }
 
 func (c CC) Left() {
-	c.SetAlt(c.nodes[0].(music.Audible).Note().Controls[c.index].Value.RandomAmount() - 1)
+	c.SetAlt(c.nodes[0].(music.Audible).Note().Controls[c.index].Value.RandomAmount() - c.step())
 }
 
 func (c CC) Right() {
-	c.SetAlt(c.nodes[0].(music.Audible).Note().Controls[c.index].Value.RandomAmount() + 1)
+	c.SetAlt(c.nodes[0].(music.Audible).Note().Controls[c.index].Value.RandomAmount() + c.step())
 }
 
 func (c CC) AltUp() {
@@ -110,7 +128,7 @@ func (c CC) AltRight() {
 
 func (c CC) Set(value int) {
 	for _, n := range c.nodes {
-		n.(music.Audible).Note().Controls[c.index].Value.Set(uint8(value))
+		n.(music.Audible).Note().Controls[c.index].Value.Set(value)
 	}
 }
 
@@ -120,16 +138,34 @@ func (c CC) SetAlt(value int) {
 	}
 }
 
-func (c CC) SetController(value uint8) {
+func (c CC) SetController(value int) {
 	for _, n := range c.nodes {
 		n.(music.Audible).Note().Controls[c.index].SetController(value)
 	}
 }
 
+// SetEditValue sets the control value, or the controller number when the
+// input is prefixed with #.
 func (c CC) SetEditValue(input string) {
+	if controller, ok := strings.CutPrefix(input, "#"); ok {
+		value, err := strconv.Atoi(controller)
+		if err != nil {
+			return
+		}
+		c.SetController(value)
+		return
+	}
 	value, err := strconv.Atoi(input)
 	if err != nil {
 		return
 	}
 	c.Set(value)
 }
+
+// step returns the value increment, coarser for 14-bit controls.
+func (c CC) step() int {
+	if c.nodes[0].(music.Audible).Note().Controls[c.index].HighRes() {
+		return highResValueStep
+	}
+	return 1
+}
@@ -0,0 +1,118 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/ui/util"
+)
+
+const (
+	ccAttackStep = 10
+)
+
+type CCShape struct {
+	index int
+	nodes []common.Node
+}
+
+func (c CCShape) Name() string {
+	return CC(c).Name()
+}
+
+func (c CCShape) Help() string {
+	if !c.cc().Shaped() {
+		return "value over note length"
+	}
+	switch c.cc().Shape {
+	case music.RampControlShape:
+		return fmt.Sprintf("ramp to %d", c.Value())
+	case music.EnvelopeControlShape:
+		return fmt.Sprintf("envelope to %d, attack %d%%", c.Value(), c.AltValue())
+	}
+	return ""
+}
+
+func (c CCShape) Display() string {
+	if !c.cc().Shaped() {
+		return "⨯"
+	}
+	switch c.cc().Shape {
+	case music.RampControlShape:
+		return fmt.Sprintf("↗%d", c.Value())
+	case music.EnvelopeControlShape:
+		return fmt.Sprintf("∧%d", c.Value())
+	}
+	return ""
+}
+
+func (c CCShape) Value() int {
+	return c.cc().End.Value()
+}
+
+func (c CCShape) AltValue() int {
+	return c.cc().Attack
+}
+
+func (c CCShape) Up() {
+	c.Set(min(c.Value()+CC(c).step(), c.cc().End.Max()))
+}
+
+func (c CCShape) Down() {
+	c.Set(max(c.Value()-CC(c).step(), 0))
+}
+
+func (c CCShape) Left() {}
+
+func (c CCShape) Right() {}
+
+func (c CCShape) AltUp() {
+	c.SetAlt(c.AltValue() + ccAttackStep)
+}
+
+func (c CCShape) AltDown() {
+	c.SetAlt(c.AltValue() - ccAttackStep)
+}
+
+func (c CCShape) AltLeft() {
+	c.setShape(util.Mod(int(c.cc().Shape)-1, len(music.AllControlShapes)))
+}
+
+func (c CCShape) AltRight() {
+	c.setShape(util.Mod(int(c.cc().Shape)+1, len(music.AllControlShapes)))
+}
+
+func (c CCShape) Set(value int) {
+	for _, n := range c.nodes {
+		n.(music.Audible).Note().Controls[c.index].End.Set(value)
+	}
+}
+
+func (c CCShape) SetAlt(value int) {
+	if value < 0 || value > music.MaxControlAttack {
+		return
+	}
+	for _, n := range c.nodes {
+		n.(music.Audible).Note().Controls[c.index].Attack = value
+	}
+}
+
+func (c CCShape) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	c.Set(value)
+}
+
+func (c CCShape) cc() *music.CC {
+	return c.nodes[0].(music.Audible).Note().Controls[c.index]
+}
+
+func (c CCShape) setShape(shape int) {
+	for _, n := range c.nodes {
+		n.(music.Audible).Note().Controls[c.index].Shape = music.ControlShape(shape)
+	}
+}
@@ -0,0 +1,140 @@
+package param
+
+import (
+	"fmt"
+
+	"signls/core/field"
+	"signls/core/music"
+	"signls/core/theory"
+	"signls/ui/util"
+)
+
+const (
+	maxChordBars = 64
+)
+
+type Chord struct {
+	grid  *field.Grid
+	index int
+}
+
+func (c Chord) Name() string {
+	return fmt.Sprintf("%d", c.index+1)
+}
+
+func (c Chord) Help() string {
+	if !c.chord().Enabled() {
+		return ""
+	}
+	bars := "bar"
+	if c.chord().Bars > 1 {
+		bars = "bars"
+	}
+	return fmt.Sprintf("%s %s for %d %s", c.chord().Key.Name(), c.chord().Scale.Name(), c.chord().Bars, bars)
+}
+
+func (c Chord) Display() string {
+	if !c.chord().Enabled() {
+		return "⨯"
+	}
+	symbol := ""
+	if c.grid.Playing && c.grid.Chord() == c.index {
+		symbol = "▸"
+	}
+	return fmt.Sprintf("%s%s %d", symbol, c.chord().Key.Name(), c.chord().Bars)
+}
+
+func (c Chord) Value() int {
+	return int(c.chord().Key)
+}
+
+func (c Chord) AltValue() int {
+	return c.chord().Bars
+}
+
+func (c Chord) Up() {
+	c.Set(c.Value() + 1)
+}
+
+func (c Chord) Down() {
+	c.Set(c.Value() - 1)
+}
+
+func (c Chord) Left() {
+	c.setScale(c.scaleIndex() - 1)
+}
+
+func (c Chord) Right() {
+	c.setScale(c.scaleIndex() + 1)
+}
+
+func (c Chord) AltUp() {
+	c.SetAlt(c.AltValue() + 1)
+}
+
+func (c Chord) AltDown() {
+	c.SetAlt(c.AltValue() - 1)
+}
+
+func (c Chord) AltLeft() {
+	c.toggle()
+}
+
+func (c Chord) AltRight() {
+	c.toggle()
+}
+
+func (c Chord) Set(value int) {
+	if !c.chord().Enabled() || value < 0 || value > maxKey {
+		return
+	}
+	c.grid.Progression[c.index].Key = theory.Key(value)
+}
+
+func (c Chord) SetAlt(value int) {
+	if !c.chord().Enabled() || value < 1 || value > maxChordBars {
+		return
+	}
+	c.grid.Progression[c.index].Bars = value
+}
+
+func (c Chord) SetEditValue(input string) {
+	key, err := music.ConvertNoteToMIDI(input)
+	if err != nil {
+		return
+	}
+	c.Set(key)
+}
+
+func (c Chord) chord() field.Chord {
+	return c.grid.Progression[c.index]
+}
+
+func (c Chord) toggle() {
+	if c.chord().Enabled() {
+		c.grid.Progression[c.index].Bars = 0
+		return
+	}
+	c.grid.Progression[c.index] = field.Chord{
+		Key:   c.grid.Key,
+		Scale: c.grid.Scale,
+		Bars:  1,
+	}
+}
+
+func (c Chord) setScale(index int) {
+	if !c.chord().Enabled() {
+		return
+	}
+	scales := theory.AllScales()
+	c.grid.Progression[c.index].Scale = scales[util.Mod(index, len(scales))]
+}
+
+func (c Chord) scaleIndex() int {
+	for i, s := range theory.AllScales() {
+		if c.chord().Scale == s {
+			return i
+		}
+	}
+	return 0
+}
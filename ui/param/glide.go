@@ -0,0 +1,104 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/core/common"
+	"signls/core/music"
+)
+
+type Glide struct {
+	nodes []common.Node
+}
+
+func (g Glide) Name() string {
+	return "gld"
+}
+
+func (g Glide) Help() string {
+	if g.Value() == 0 {
+		return ""
+	}
+	if g.mode() == music.PitchBendGlideMode {
+		return fmt.Sprintf("pitch bend slide over %d pulses", g.Value())
+	}
+	return fmt.Sprintf("portamento over %d pulses", g.Value())
+}
+
+func (g Glide) Display() string {
+	if g.Value() == 0 {
+		return "⨯"
+	}
+	if g.mode() == music.PitchBendGlideMode {
+		return fmt.Sprintf("%dpb", g.Value())
+	}
+	return fmt.Sprintf("%dcc", g.Value())
+}
+
+func (g Glide) Value() int {
+	return g.nodes[0].(music.Audible).Note().Glide
+}
+
+func (g Glide) AltValue() int {
+	return int(g.mode())
+}
+
+func (g Glide) Up() {
+	g.Set(g.Value() + 1)
+}
+
+func (g Glide) Down() {
+	g.Set(g.Value() - 1)
+}
+
+func (g Glide) Left() {}
+
+func (g Glide) Right() {}
+
+func (g Glide) AltUp() {}
+
+func (g Glide) AltDown() {}
+
+func (g Glide) AltLeft() {
+	g.toggleMode()
+}
+
+func (g Glide) AltRight() {
+	g.toggleMode()
+}
+
+func (g Glide) Set(value int) {
+	if value < 0 || value > music.MaxGlide {
+		return
+	}
+	for _, n := range g.nodes {
+		n.(music.Audible).Note().Glide = value
+	}
+}
+
+func (g Glide) SetAlt(value int) {
+	for _, n := range g.nodes {
+		n.(music.Audible).Note().GlideMode = music.GlideMode(value)
+	}
+}
+
+func (g Glide) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	g.Set(value)
+}
+
+func (g Glide) mode() music.GlideMode {
+	return g.nodes[0].(music.Audible).Note().GlideMode
+}
+
+func (g Glide) toggleMode() {
+	if g.mode() == music.PitchBendGlideMode {
+		g.SetAlt(int(music.PortamentoGlideMode))
+		return
+	}
+	g.SetAlt(int(music.PitchBendGlideMode))
+}
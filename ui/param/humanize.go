@@ -0,0 +1,70 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/core/field"
+	"signls/core/music"
+)
+
+type Humanize struct {
+	grid *field.Grid
+}
+
+func (h Humanize) Name() string {
+	return "humanize"
+}
+
+func (h Humanize) Help() string {
+	return "random timing and velocity depth"
+}
+
+func (h Humanize) Display() string {
+	return fmt.Sprintf("%d%%", h.grid.Performance.Humanize)
+}
+
+func (h Humanize) Value() int {
+	return h.grid.Performance.Humanize
+}
+
+func (h Humanize) AltValue() int {
+	return 0
+}
+
+func (h Humanize) Up() {
+	h.Set(h.Value() + 1)
+}
+
+func (h Humanize) Down() {
+	h.Set(h.Value() - 1)
+}
+
+func (h Humanize) Left() {}
+
+func (h Humanize) Right() {}
+
+func (h Humanize) AltUp() {}
+
+func (h Humanize) AltDown() {}
+
+func (h Humanize) AltLeft() {}
+
+func (h Humanize) AltRight() {}
+
+func (h Humanize) Set(value int) {
+	if value < 0 || value > music.MaxHumanize {
+		return
+	}
+	h.grid.Performance.Humanize = value
+}
+
+func (h Humanize) SetAlt(value int) {}
+
+func (h Humanize) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	h.Set(value)
+}
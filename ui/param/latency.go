@@ -0,0 +1,70 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/core/field"
+	"signls/core/music"
+)
+
+type Latency struct {
+	grid *field.Grid
+}
+
+func (l Latency) Name() string {
+	return "latency"
+}
+
+func (l Latency) Help() string {
+	return "delay all notes, in pulses"
+}
+
+func (l Latency) Display() string {
+	return fmt.Sprintf("%d", l.grid.Performance.Latency)
+}
+
+func (l Latency) Value() int {
+	return l.grid.Performance.Latency
+}
+
+func (l Latency) AltValue() int {
+	return 0
+}
+
+func (l Latency) Up() {
+	l.Set(l.Value() + 1)
+}
+
+func (l Latency) Down() {
+	l.Set(l.Value() - 1)
+}
+
+func (l Latency) Left() {}
+
+func (l Latency) Right() {}
+
+func (l Latency) AltUp() {}
+
+func (l Latency) AltDown() {}
+
+func (l Latency) AltLeft() {}
+
+func (l Latency) AltRight() {}
+
+func (l Latency) Set(value int) {
+	if value < 0 || value > music.MaxLatency {
+		return
+	}
+	l.grid.Performance.Latency = value
+}
+
+func (l Latency) SetAlt(value int) {}
+
+func (l Latency) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	l.Set(value)
+}
@@ -0,0 +1,68 @@
+package param
+
+import (
+	"signls/core/common"
+	"signls/core/music"
+)
+
+type Legato struct {
+	nodes []common.Node
+}
+
+func (l Legato) Name() string {
+	return "leg"
+}
+
+func (l Legato) Help() string {
+	if l.nodes[0].(music.Audible).Note().Legato {
+		return "next note sent before release"
+	}
+	return ""
+}
+
+func (l Legato) Display() string {
+	if l.nodes[0].(music.Audible).Note().Legato {
+		return "on"
+	}
+	return "off"
+}
+
+func (l Legato) Value() int {
+	return 0
+}
+
+func (l Legato) AltValue() int {
+	return 0
+}
+
+func (l Legato) Up() {
+	l.set(true)
+}
+
+func (l Legato) Down() {
+	l.set(false)
+}
+
+func (l Legato) Left() {}
+
+func (l Legato) Right() {}
+
+func (l Legato) AltUp() {}
+
+func (l Legato) AltDown() {}
+
+func (l Legato) AltLeft() {}
+
+func (l Legato) AltRight() {}
+
+func (l Legato) Set(value int) {}
+
+func (l Legato) SetAlt(value int) {}
+
+func (l Legato) SetEditValue(input string) {}
+
+func (l Legato) set(legato bool) {
+	for _, n := range l.nodes {
+		n.(music.Audible).Note().Legato = legato
+	}
+}
@@ -2,17 +2,12 @@ package param
 
 import (
 	"fmt"
-	"strconv"
 
 	"signls/core/common"
 	"signls/core/music"
 	"signls/ui/util"
 )
 
-const (
-	maxLength = 127
-)
-
 type Length struct {
 	nodes []common.Node
 }
@@ -22,37 +17,28 @@ func (l Length) Name() string {
 }
 
 func (l Length) Help() string {
+	if l.note().Tie {
+		return "hold until next trigger"
+	}
 	return ""
 }
 
 func (l Length) Display() string {
-	length := int(l.nodes[0].(music.Audible).Note().Length.Value())
-	pulsesPerStep, stepsPerQuarterNote := l.nodes[0].(music.Audible).Note().ClockDivision()
-	var display string
-	switch length {
-	case pulsesPerStep / 4:
-		display = "1|64"
-	case pulsesPerStep / 2:
-		display = "1|32"
-	case pulsesPerStep:
-		display = "1|16"
-	case pulsesPerStep * stepsPerQuarterNote / 2:
-		display = "1|8"
-	case pulsesPerStep * stepsPerQuarterNote:
-		display = "1|4"
-	case pulsesPerStep * stepsPerQuarterNote * 2:
-		display = "1|2"
-	case maxLength:
-		display = "inf"
-	default:
+	if l.note().Tie {
+		return "tie"
+	}
+	length := l.note().Length.Value()
+	pulsesPerStep, _ := l.note().ClockDivision()
+	display, ok := music.NoteLengthName(length)
+	if !ok {
 		display = fmt.Sprintf("%.1f", float64(length)/float64(pulsesPerStep))
 	}
-	if l.nodes[0].(music.Audible).Note().Length.RandomAmount() != 0 {
+	if l.note().Length.RandomAmount() != 0 {
 		return util.Normalize(
 			fmt.Sprintf(
 				"%s%+.1f\u033c",
 				display,
-				float64(l.nodes[0].(music.Audible).Note().Length.RandomAmount())/float64(pulsesPerStep),
+				float64(l.note().Length.RandomAmount())/float64(pulsesPerStep),
 			),
 		)
 	}
@@ -60,7 +46,7 @@ func (l Length) Display() string {
 }
 
 func (l Length) Value() int {
-	return int(l.nodes[0].(music.Audible).Note().Length.Value())
+	return l.note().Length.Value()
 }
 
 func (l Length) AltValue() int {
@@ -68,32 +54,36 @@ func (l Length) AltValue() int {
 }
 
 func (l Length) Up() {
-	l.Set(l.Value() + 1)
+	l.Set(music.NextNoteLength(l.Value()))
 }
 
 func (l Length) Down() {
-	l.Set(l.Value() - 1)
+	l.Set(music.PreviousNoteLength(l.Value()))
 }
 
 func (l Length) Left() {
-	l.SetAlt(l.nodes[0].(music.Audible).Note().Length.RandomAmount() - 1)
+	l.SetAlt(l.note().Length.RandomAmount() - 1)
 }
 
 func (l Length) Right() {
-	l.SetAlt(l.nodes[0].(music.Audible).Note().Length.RandomAmount() + 1)
+	l.SetAlt(l.note().Length.RandomAmount() + 1)
 }
 
 func (l Length) AltUp() {}
 
 func (l Length) AltDown() {}
 
-func (l Length) AltLeft() {}
+func (l Length) AltLeft() {
+	l.toggleTie()
+}
 
-func (l Length) AltRight() {}
+func (l Length) AltRight() {
+	l.toggleTie()
+}
 
 func (l Length) Set(value int) {
 	for _, n := range l.nodes {
-		n.(music.Audible).Note().SetLength(uint8(value))
+		n.(music.Audible).Note().SetLength(value)
 	}
 }
 
@@ -104,9 +94,20 @@ func (l Length) SetAlt(value int) {
 }
 
 func (l Length) SetEditValue(input string) {
-	value, err := strconv.Atoi(input)
+	value, err := music.ParseNoteLength(input)
 	if err != nil {
 		return
 	}
 	l.Set(value)
 }
+
+func (l Length) note() *music.Note {
+	return l.nodes[0].(music.Audible).Note()
+}
+
+func (l Length) toggleTie() {
+	tie := !l.note().Tie
+	for _, n := range l.nodes {
+		n.(music.Audible).Note().Tie = tie
+	}
+}
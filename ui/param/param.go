@@ -6,10 +6,12 @@ import (
 	"signls/core/music"
 	"signls/core/node"
 	"signls/core/theory"
+	"signls/filesystem"
 )
 
 const (
 	defaultControlParamsNumber = 8
+	songEntriesPerPage         = 8
 )
 
 type Param interface {
@@ -53,7 +55,9 @@ func NewParamsForNodes(grid *field.Grid, nodes []common.Node) [][]Param {
 				DefaultEmitterParams(grid, nodes),
 				Threshold{nodes: nodes},
 			),
+			DefaultEmitterArticulations(nodes),
 			DefaultEmitterControlChanges(nodes),
+			DefaultEmitterControlShapes(nodes),
 			DefaultEmitterMetaCommands(nodes),
 		}
 	} else if isHomogeneousNode[*node.EuclidEmitter](nodes) {
@@ -64,7 +68,9 @@ func NewParamsForNodes(grid *field.Grid, nodes []common.Node) [][]Param {
 				Triggers{nodes: nodes},
 				Offset{nodes: nodes},
 			),
+			DefaultEmitterArticulations(nodes),
 			DefaultEmitterControlChanges(nodes),
+			DefaultEmitterControlShapes(nodes),
 			DefaultEmitterMetaCommands(nodes),
 		}
 	} else if isHomogeneousBehavior[common.Repeatable](nodes) {
@@ -73,7 +79,9 @@ func NewParamsForNodes(grid *field.Grid, nodes []common.Node) [][]Param {
 				DefaultEmitterParams(grid, nodes),
 				Repeat{nodes: nodes},
 			),
+			DefaultEmitterArticulations(nodes),
 			DefaultEmitterControlChanges(nodes),
+			DefaultEmitterControlShapes(nodes),
 			DefaultEmitterMetaCommands(nodes),
 		}
 	}
@@ -82,7 +90,9 @@ func NewParamsForNodes(grid *field.Grid, nodes []common.Node) [][]Param {
 
 	return [][]Param{
 		DefaultEmitterParams(grid, emitters),
+		DefaultEmitterArticulations(emitters),
 		DefaultEmitterControlChanges(emitters),
+		DefaultEmitterControlShapes(emitters),
 		DefaultEmitterMetaCommands(emitters),
 	}
 }
@@ -108,11 +118,29 @@ func DefaultEmitterParams(grid *field.Grid, nodes []common.Node) []Param {
 	}
 }
 
+func DefaultEmitterArticulations(nodes []common.Node) []Param {
+	return []Param{
+		Timing{nodes: nodes},
+		Ratchet{nodes: nodes},
+		Legato{nodes: nodes},
+		Glide{nodes: nodes},
+		Release{nodes: nodes},
+	}
+}
+
 func DefaultEmitterControlChanges(nodes []common.Node) []Param {
 	params := make([]Param, defaultControlParamsNumber)
 	for i := range params {
 		params[i] = CC{index: i, nodes: nodes}
 	}
+	return append(params, SysEx{nodes: nodes})
+}
+
+func DefaultEmitterControlShapes(nodes []common.Node) []Param {
+	params := make([]Param, defaultControlParamsNumber)
+	for i := range params {
+		params[i] = CCShape{index: i, nodes: nodes}
+	}
 	return params
 }
 
@@ -132,14 +160,44 @@ func NewParamsForGrid(grid *field.Grid) []Param {
 	}
 }
 
-func NewParamsForMidi(grid *field.Grid) [][]Param {
+func NewParamsForMidi(grid *field.Grid, bank *filesystem.Bank) [][]Param {
 	return [][]Param{
 		{
 			ClockSend{grid: grid},
 			TransportSend{grid: grid},
 			DefaultDevice{grid: grid},
+			Latency{grid: grid},
+			Humanize{grid: grid},
+			BendRange{grid: grid},
+			Quantize{bank: bank},
+		},
+	}
+}
+
+func NewParamsForProgression(grid *field.Grid) []Param {
+	params := make([]Param, field.MaxChords)
+	for i := range params {
+		params[i] = Chord{grid: grid, index: i}
+	}
+	return params
+}
+
+func NewParamsForSong(bank *filesystem.Bank) [][]Param {
+	params := [][]Param{
+		{
+			SongPlay{song: &bank.Song},
+			SongLoop{song: &bank.Song},
+			SongLoop{song: &bank.Song, end: true},
 		},
 	}
+	for i := 0; i < filesystem.MaxSongEntries; i += songEntriesPerPage {
+		page := []Param{}
+		for j := i; j < i+songEntriesPerPage && j < filesystem.MaxSongEntries; j++ {
+			page = append(page, SongEntry{song: &bank.Song, index: j})
+		}
+		params = append(params, page)
+	}
+	return params
 }
 
 func Get(name string, params []Param) Param {
@@ -0,0 +1,81 @@
+package param
+
+import (
+	"fmt"
+
+	"signls/core/common"
+	"signls/filesystem"
+)
+
+// quantizeSteps lists the available grid switching quantizations in steps.
+var quantizeSteps = []int{0, 4, 16, 32, 64, 128}
+
+type Quantize struct {
+	bank *filesystem.Bank
+}
+
+func (q Quantize) Name() string {
+	return "quantize"
+}
+
+func (q Quantize) Help() string {
+	return "grid switching boundary, saved with the bank"
+}
+
+func (q Quantize) Display() string {
+	stepsPerBar := common.StepsPerQuarterNote * common.QuarterNotesPerBar
+	switch steps := q.bank.Quantize; {
+	case steps == 0:
+		return "step"
+	case steps < stepsPerBar:
+		return "beat"
+	case steps == stepsPerBar:
+		return "1 bar"
+	default:
+		return fmt.Sprintf("%d bars", steps/stepsPerBar)
+	}
+}
+
+func (q Quantize) Value() int {
+	for i, steps := range quantizeSteps {
+		if steps >= q.bank.Quantize {
+			return i
+		}
+	}
+	return len(quantizeSteps) - 1
+}
+
+func (q Quantize) AltValue() int {
+	return 0
+}
+
+func (q Quantize) Up() {
+	q.Set(q.Value() + 1)
+}
+
+func (q Quantize) Down() {
+	q.Set(q.Value() - 1)
+}
+
+func (q Quantize) Left() {}
+
+func (q Quantize) Right() {}
+
+func (q Quantize) AltUp() {}
+
+func (q Quantize) AltDown() {}
+
+func (q Quantize) AltLeft() {}
+
+func (q Quantize) AltRight() {}
+
+func (q Quantize) Set(value int) {
+	if value < 0 || value >= len(quantizeSteps) {
+		return
+	}
+	q.bank.Quantize = quantizeSteps[value]
+}
+
+func (q Quantize) SetAlt(value int) {}
+
+func (q Quantize) SetEditValue(input string) {}
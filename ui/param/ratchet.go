@@ -0,0 +1,114 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/ui/util"
+)
+
+const (
+	ratchetRampStep = 10
+)
+
+type Ratchet struct {
+	nodes []common.Node
+}
+
+func (r Ratchet) Name() string {
+	return "rat"
+}
+
+func (r Ratchet) Help() string {
+	if r.AltValue() == 0 {
+		return "hits per trigger"
+	}
+	return fmt.Sprintf("velocity ramp %+d%%", r.AltValue())
+}
+
+func (r Ratchet) Display() string {
+	display := fmt.Sprintf("%d", r.Value())
+	if r.note().Ratchet.RandomAmount() != 0 {
+		display = util.Normalize(
+			fmt.Sprintf("%d%+d\u033c", r.Value(), r.note().Ratchet.RandomAmount()),
+		)
+	}
+	switch {
+	case r.AltValue() > 0:
+		return fmt.Sprintf("%s↗", display)
+	case r.AltValue() < 0:
+		return fmt.Sprintf("%s↘", display)
+	}
+	return display
+}
+
+func (r Ratchet) Value() int {
+	return r.note().Ratchet.Value()
+}
+
+func (r Ratchet) AltValue() int {
+	return r.note().RatchetRamp
+}
+
+func (r Ratchet) Up() {
+	r.Set(r.Value() + 1)
+}
+
+func (r Ratchet) Down() {
+	r.Set(r.Value() - 1)
+}
+
+func (r Ratchet) Left() {
+	r.setRandomAmount(r.note().Ratchet.RandomAmount() - 1)
+}
+
+func (r Ratchet) Right() {
+	r.setRandomAmount(r.note().Ratchet.RandomAmount() + 1)
+}
+
+func (r Ratchet) AltUp() {
+	r.SetAlt(r.AltValue() + ratchetRampStep)
+}
+
+func (r Ratchet) AltDown() {
+	r.SetAlt(r.AltValue() - ratchetRampStep)
+}
+
+func (r Ratchet) AltLeft() {}
+
+func (r Ratchet) AltRight() {}
+
+func (r Ratchet) Set(value int) {
+	for _, n := range r.nodes {
+		n.(music.Audible).Note().Ratchet.Set(value)
+	}
+}
+
+func (r Ratchet) SetAlt(value int) {
+	if value < -music.MaxRatchetRamp || value > music.MaxRatchetRamp {
+		return
+	}
+	for _, n := range r.nodes {
+		n.(music.Audible).Note().RatchetRamp = value
+	}
+}
+
+func (r Ratchet) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	r.Set(value)
+}
+
+func (r Ratchet) note() *music.Note {
+	return r.nodes[0].(music.Audible).Note()
+}
+
+func (r Ratchet) setRandomAmount(amount int) {
+	for _, n := range r.nodes {
+		n.(music.Audible).Note().Ratchet.SetRandomAmount(amount)
+	}
+}
@@ -0,0 +1,87 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/ui/util"
+)
+
+type Release struct {
+	nodes []common.Node
+}
+
+func (r Release) Name() string {
+	return "rel"
+}
+
+func (r Release) Help() string {
+	return "note off velocity"
+}
+
+func (r Release) Display() string {
+	if r.nodes[0].(music.Audible).Note().Release.RandomAmount() != 0 {
+		return util.Normalize(
+			fmt.Sprintf(
+				"%d%+d\u033c",
+				r.nodes[0].(music.Audible).Note().Release.Value(),
+				r.nodes[0].(music.Audible).Note().Release.RandomAmount(),
+			),
+		)
+	}
+	return fmt.Sprintf("%d", r.nodes[0].(music.Audible).Note().Release.Value())
+}
+
+func (r Release) Value() int {
+	return int(r.nodes[0].(music.Audible).Note().Release.Value())
+}
+
+func (r Release) AltValue() int {
+	return 0
+}
+
+func (r Release) Up() {
+	r.Set(r.Value() + 1)
+}
+
+func (r Release) Down() {
+	r.Set(r.Value() - 1)
+}
+
+func (r Release) Left() {
+	r.SetAlt(r.nodes[0].(music.Audible).Note().Release.RandomAmount() - 1)
+}
+
+func (r Release) Right() {
+	r.SetAlt(r.nodes[0].(music.Audible).Note().Release.RandomAmount() + 1)
+}
+
+func (r Release) AltUp() {}
+
+func (r Release) AltDown() {}
+
+func (r Release) AltLeft() {}
+
+func (r Release) AltRight() {}
+
+func (r Release) Set(value int) {
+	for _, n := range r.nodes {
+		n.(music.Audible).Note().Release.Set(uint8(value))
+	}
+}
+
+func (r Release) SetAlt(value int) {
+	for _, n := range r.nodes {
+		n.(music.Audible).Note().Release.SetRandomAmount(value)
+	}
+}
+
+func (r Release) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	r.Set(value)
+}
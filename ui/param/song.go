@@ -0,0 +1,261 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/filesystem"
+)
+
+const (
+	maxSongGrid    = 32
+	maxSongBars    = 64
+	maxSongRepeats = 16
+
+	defaultSongBars = 4
+)
+
+type SongPlay struct {
+	song *filesystem.Song
+}
+
+func (s SongPlay) Name() string {
+	return "song"
+}
+
+func (s SongPlay) Help() string {
+	return ""
+}
+
+func (s SongPlay) Display() string {
+	if s.song.Playing() {
+		return "on"
+	}
+	return "off"
+}
+
+func (s SongPlay) Value() int {
+	return 0
+}
+
+func (s SongPlay) AltValue() int {
+	return 0
+}
+
+func (s SongPlay) Up() {
+	if s.song.Playing() {
+		return
+	}
+	s.song.Start()
+}
+
+func (s SongPlay) Down() {
+	s.song.Stop()
+}
+
+func (s SongPlay) Left() {}
+
+func (s SongPlay) Right() {}
+
+func (s SongPlay) AltUp() {}
+
+func (s SongPlay) AltDown() {}
+
+func (s SongPlay) AltLeft() {}
+
+func (s SongPlay) AltRight() {}
+
+func (s SongPlay) Set(value int) {}
+
+func (s SongPlay) SetAlt(value int) {}
+
+func (s SongPlay) SetEditValue(input string) {}
+
+type SongLoop struct {
+	song *filesystem.Song
+	end  bool
+}
+
+func (s SongLoop) Name() string {
+	if s.end {
+		return "loop end"
+	}
+	return "loop start"
+}
+
+func (s SongLoop) Help() string {
+	return ""
+}
+
+func (s SongLoop) Display() string {
+	return fmt.Sprintf("%d", s.Value()+1)
+}
+
+func (s SongLoop) Value() int {
+	if s.end {
+		return s.song.LoopEnd
+	}
+	return s.song.LoopStart
+}
+
+func (s SongLoop) AltValue() int {
+	return 0
+}
+
+func (s SongLoop) Up() {
+	s.Set(s.Value() + 1)
+}
+
+func (s SongLoop) Down() {
+	s.Set(s.Value() - 1)
+}
+
+func (s SongLoop) Left() {}
+
+func (s SongLoop) Right() {}
+
+func (s SongLoop) AltUp() {}
+
+func (s SongLoop) AltDown() {}
+
+func (s SongLoop) AltLeft() {}
+
+func (s SongLoop) AltRight() {}
+
+func (s SongLoop) Set(value int) {
+	if value < 0 || value >= filesystem.MaxSongEntries {
+		return
+	}
+	if s.end && value >= s.song.LoopStart {
+		s.song.LoopEnd = value
+	} else if !s.end && value <= s.song.LoopEnd {
+		s.song.LoopStart = value
+	}
+}
+
+func (s SongLoop) SetAlt(value int) {}
+
+func (s SongLoop) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	s.Set(value - 1)
+}
+
+type SongEntry struct {
+	song  *filesystem.Song
+	index int
+}
+
+func (s SongEntry) Name() string {
+	return fmt.Sprintf("%d", s.index+1)
+}
+
+func (s SongEntry) Help() string {
+	if !s.entry().Enabled() {
+		return ""
+	}
+	return fmt.Sprintf(
+		"grid %d for %d bars, %d times",
+		s.entry().Grid+1,
+		s.entry().Bars,
+		max(s.entry().Repeat, 1),
+	)
+}
+
+func (s SongEntry) Display() string {
+	if !s.entry().Enabled() {
+		return "⨯"
+	}
+	symbol := ""
+	if s.song.Playing() && s.song.Entry() == s.index {
+		symbol = "▸"
+	}
+	return fmt.Sprintf("%s%d %d×%d", symbol, s.entry().Grid+1, s.entry().Bars, max(s.entry().Repeat, 1))
+}
+
+func (s SongEntry) Value() int {
+	return s.entry().Grid
+}
+
+func (s SongEntry) AltValue() int {
+	return s.entry().Bars
+}
+
+func (s SongEntry) Up() {
+	s.Set(s.Value() + 1)
+}
+
+func (s SongEntry) Down() {
+	s.Set(s.Value() - 1)
+}
+
+func (s SongEntry) Left() {
+	s.setRepeat(s.entry().Repeat - 1)
+}
+
+func (s SongEntry) Right() {
+	s.setRepeat(s.entry().Repeat + 1)
+}
+
+func (s SongEntry) AltUp() {
+	s.SetAlt(s.AltValue() + 1)
+}
+
+func (s SongEntry) AltDown() {
+	s.SetAlt(s.AltValue() - 1)
+}
+
+func (s SongEntry) AltLeft() {
+	s.toggle()
+}
+
+func (s SongEntry) AltRight() {
+	s.toggle()
+}
+
+func (s SongEntry) Set(value int) {
+	if !s.entry().Enabled() || value < 0 || value >= maxSongGrid {
+		return
+	}
+	s.song.Entries[s.index].Grid = value
+}
+
+func (s SongEntry) SetAlt(value int) {
+	if !s.entry().Enabled() || value < 1 || value > maxSongBars {
+		return
+	}
+	s.song.Entries[s.index].Bars = value
+}
+
+func (s SongEntry) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	s.Set(value - 1)
+}
+
+func (s SongEntry) entry() filesystem.SongEntry {
+	return s.song.Entries[s.index]
+}
+
+func (s SongEntry) setRepeat(value int) {
+	if !s.entry().Enabled() || value < 1 || value > maxSongRepeats {
+		return
+	}
+	s.song.Entries[s.index].Repeat = value
+}
+
+func (s SongEntry) toggle() {
+	if s.entry().Enabled() {
+		s.song.Entries[s.index].Bars = 0
+		return
+	}
+	s.song.Entries[s.index] = filesystem.SongEntry{
+		Grid:   s.entry().Grid,
+		Bars:   defaultSongBars,
+		Repeat: 1,
+	}
+}
@@ -0,0 +1,100 @@
+package param
+
+import (
+	"fmt"
+	"strings"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/ui/util"
+)
+
+type SysEx struct {
+	nodes []common.Node
+}
+
+func (s SysEx) Name() string {
+	return "sx"
+}
+
+func (s SysEx) Help() string {
+	if !s.sysex().Active() {
+		return "hex payload, vv for value"
+	}
+	return strings.ToLower(s.sysex().Payload)
+}
+
+func (s SysEx) Display() string {
+	if !s.sysex().Active() {
+		return "⨯"
+	}
+	if !s.sysex().HasValue() {
+		return "on"
+	}
+	if s.sysex().Value.RandomAmount() != 0 {
+		return util.Normalize(
+			fmt.Sprintf(
+				"%d%+d\u033c",
+				s.sysex().Value.Value(),
+				s.sysex().Value.RandomAmount(),
+			),
+		)
+	}
+	return fmt.Sprintf("%d", s.sysex().Value.Value())
+}
+
+func (s SysEx) Value() int {
+	return s.sysex().Value.Value()
+}
+
+func (s SysEx) AltValue() int {
+	return 0
+}
+
+func (s SysEx) Up() {
+	s.Set(s.Value() + 1)
+}
+
+func (s SysEx) Down() {
+	s.Set(s.Value() - 1)
+}
+
+func (s SysEx) Left() {
+	s.SetAlt(s.sysex().Value.RandomAmount() - 1)
+}
+
+func (s SysEx) Right() {
+	s.SetAlt(s.sysex().Value.RandomAmount() + 1)
+}
+
+func (s SysEx) AltUp() {}
+
+func (s SysEx) AltDown() {}
+
+func (s SysEx) AltLeft() {}
+
+func (s SysEx) AltRight() {}
+
+func (s SysEx) Set(value int) {
+	for _, n := range s.nodes {
+		n.(music.Audible).Note().SysEx.Value.Set(value)
+	}
+}
+
+func (s SysEx) SetAlt(value int) {
+	for _, n := range s.nodes {
+		n.(music.Audible).Note().SysEx.Value.SetRandomAmount(value)
+	}
+}
+
+func (s SysEx) SetEditValue(input string) {
+	for _, n := range s.nodes {
+		if err := n.(music.Audible).Note().SysEx.SetPayload(input); err != nil {
+			return
+		}
+	}
+}
+
+func (s SysEx) sysex() *music.SysEx {
+	return s.nodes[0].(music.Audible).Note().SysEx
+}
@@ -0,0 +1,87 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+
+	"signls/core/common"
+	"signls/core/music"
+	"signls/ui/util"
+)
+
+type Timing struct {
+	nodes []common.Node
+}
+
+func (t Timing) Name() string {
+	return "tim"
+}
+
+func (t Timing) Help() string {
+	return "offset in pulses, early notes sent from the previous step"
+}
+
+func (t Timing) Display() string {
+	if t.nodes[0].(music.Audible).Note().Timing.RandomAmount() != 0 {
+		return util.Normalize(
+			fmt.Sprintf(
+				"%+d%+d\u033c",
+				t.nodes[0].(music.Audible).Note().Timing.Value(),
+				t.nodes[0].(music.Audible).Note().Timing.RandomAmount(),
+			),
+		)
+	}
+	return fmt.Sprintf("%+d", t.nodes[0].(music.Audible).Note().Timing.Value())
+}
+
+func (t Timing) Value() int {
+	return t.nodes[0].(music.Audible).Note().Timing.Value()
+}
+
+func (t Timing) AltValue() int {
+	return 0
+}
+
+func (t Timing) Up() {
+	t.Set(t.Value() + 1)
+}
+
+func (t Timing) Down() {
+	t.Set(t.Value() - 1)
+}
+
+func (t Timing) Left() {
+	t.SetAlt(t.nodes[0].(music.Audible).Note().Timing.RandomAmount() - 1)
+}
+
+func (t Timing) Right() {
+	t.SetAlt(t.nodes[0].(music.Audible).Note().Timing.RandomAmount() + 1)
+}
+
+func (t Timing) AltUp() {}
+
+func (t Timing) AltDown() {}
+
+func (t Timing) AltLeft() {}
+
+func (t Timing) AltRight() {}
+
+func (t Timing) Set(value int) {
+	for _, n := range t.nodes {
+		n.(music.Audible).Note().Timing.Set(value)
+	}
+}
+
+func (t Timing) SetAlt(value int) {
+	for _, n := range t.nodes {
+		n.(music.Audible).Note().Timing.SetRandomAmount(value)
+	}
+}
+
+func (t Timing) SetEditValue(input string) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return
+	}
+	t.Set(value)
+}
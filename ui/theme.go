@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+
+	"signls/core/common"
+	"signls/filesystem"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// colorTags is the number of colors bank grids can be tagged with.
+const colorTags = 8
+
+// theme holds the ui colors, set from the configuration.
+var theme filesystem.Theme
+
+var (
+	gridStyle                lipgloss.Style
+	cursorStyle              lipgloss.Style
+	teleportDestinationStyle lipgloss.Style
+	selectionStyle           lipgloss.Style
+	emitterStyle             lipgloss.Style
+	mutedEmitterStyle        lipgloss.Style
+	activeEmitterStyle       lipgloss.Style
+
+	activeCellStyle     lipgloss.Style
+	bankStyle           lipgloss.Style
+	bankStyleOdd        lipgloss.Style
+	queuedBankStyle     lipgloss.Style
+	layerBankStyle      lipgloss.Style
+	mutedLayerBankStyle lipgloss.Style
+	soloLayerBankStyle  lipgloss.Style
+	activeBankStyle     lipgloss.Style
+	errorStyle          lipgloss.Style
+)
+
+// applyTheme builds the ui styles from the theme colors.
+func applyTheme(t filesystem.Theme) {
+	theme = t
+
+	gridStyle = lipgloss.NewStyle().
+		Background(themeColor("grid"))
+	cursorStyle = lipgloss.NewStyle().
+		Background(themeColor("cursor")).
+		Foreground(themeColor("cursor_text"))
+	teleportDestinationStyle = lipgloss.NewStyle().
+		Background(themeColor("destination")).
+		Foreground(themeColor("destination_text"))
+	selectionStyle = lipgloss.NewStyle().
+		Background(themeColor("selection")).
+		Foreground(themeColor("selection_text"))
+	emitterStyle = lipgloss.NewStyle().
+		Foreground(themeColor("node_text"))
+	mutedEmitterStyle = lipgloss.NewStyle().
+		Background(themeColor("muted")).
+		Foreground(themeColor("muted_text"))
+	activeEmitterStyle = lipgloss.NewStyle().
+		Background(themeColor("active")).
+		Foreground(themeColor("active_text"))
+
+	activeCellStyle = cellStyle.
+		Foreground(themeColor("highlight"))
+	bankStyle = newBankStyle("bank")
+	bankStyleOdd = newBankStyle("bank_alt")
+	queuedBankStyle = newBankStyle("bank_queued")
+	layerBankStyle = newBankStyle("bank_layer")
+	mutedLayerBankStyle = newBankStyle("bank_muted")
+	soloLayerBankStyle = newBankStyle("bank_solo")
+	activeBankStyle = newBankStyle("bank_active")
+	errorStyle = lipgloss.NewStyle().
+		MarginLeft(2).
+		Foreground(themeColor("error"))
+}
+
+func newBankStyle(element string) lipgloss.Style {
+	return lipgloss.NewStyle().
+		MarginRight(1).
+		Background(themeColor(element)).
+		Foreground(themeColor("bank_text"))
+}
+
+// themeColor returns the color of a ui element, adapted to light terminals
+// when the theme defines it.
+func themeColor(element string) lipgloss.TerminalColor {
+	if light := theme.Color(element + "_light"); light != "" {
+		return lipgloss.AdaptiveColor{Light: light, Dark: theme.Color(element)}
+	}
+	return lipgloss.Color(theme.Color(element))
+}
+
+// nodeColor returns the color of a node type.
+func nodeColor(n common.Node) lipgloss.TerminalColor {
+	return themeColor(n.Name())
+}
+
+// nodeTextColor returns the color of a node symbol, drawn over the node
+// color.
+func nodeTextColor(n common.Node) lipgloss.TerminalColor {
+	if theme.Color(n.Name()+"_text") != "" {
+		return themeColor(n.Name() + "_text")
+	}
+	return themeColor("node_text")
+}
+
+// tagColor returns the color a bank grid is tagged with.
+func tagColor(g filesystem.Grid) (lipgloss.TerminalColor, bool) {
+	if g.Color <= 0 || g.Color > colorTags {
+		return nil, false
+	}
+	return themeColor(fmt.Sprintf("tag_%d", g.Color)), true
+}
@@ -1,16 +1,20 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"signls/core/common"
 	"signls/core/field"
+	"signls/core/music"
 	"signls/core/node"
 	"signls/filesystem"
 	"signls/ui/param"
 	"signls/ui/util"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -25,9 +29,17 @@ const (
 
 	blinkFrequency = 500 * time.Millisecond
 
+	autosaveFrequency = time.Minute
+
+	watchFrequency = time.Second
+
 	controlsHeight = 4
 
 	helpHeader = "signls %s - docs: https://empr.cl/signls/"
+
+	// Long enough for sysex payloads.
+	inputCharLimit = 64
+	notesCharLimit = 256
 )
 
 // mode is a representation of a ui mode
@@ -42,6 +54,21 @@ const (
 	CONFIG
 	// BANK mode allows bank grids selection
 	BANK
+	// SONG mode allows song arrangement edits
+	SONG
+	// PATTERN mode allows pattern files import and export
+	PATTERN
+	// MIXER mode allows channels and devices mutes and solos
+	MIXER
+)
+
+// bankInput is the bank grid field edited by the text input
+type bankInput uint8
+
+const (
+	bankInputName bankInput = iota
+	bankInputNotes
+	bankInputSearch
 )
 
 // tickMsg is a message that triggers ui rrefresh
@@ -53,6 +80,13 @@ type blinkMsg time.Time
 // saveMsg is a message that notify a successfull save
 type saveMsg bool
 
+// autosaveMsg is a message that triggers saving the playing grids
+type autosaveMsg time.Time
+
+// watchMsg is a message that triggers checking the bank file for external
+// changes
+type watchMsg time.Time
+
 type mainModel struct {
 	bank          *filesystem.Bank
 	grid          *field.Grid
@@ -70,19 +104,41 @@ type mainModel struct {
 	selectionX    int
 	selectionY    int
 	selectedGrid  int
+	mixerStrip    int
+	bankInput     bankInput
+	query         *field.Query // Nodes selected by query instead of the cursor.
+	sized         bool         // The grid keeps the size set by the resize command.
+	command       bool         // The text input is the command prompt.
+	completions   []string     // Command completion candidates.
+	notePane      notePane
+	dragging      bool
+	dragX         int
+	dragY         int
 	param         int
 	paramPage     int
 	blink         bool
 	mute          bool
+	configErr     error
+
+	patternsDir     string
+	patterns        []string
+	selectedPattern int
+	fileErr         error // Pattern, text export, search or query error.
+
+	recovery string // Backup offered for recovery when the bank cannot be read.
+	reload   []int  // Playing grids changed on disk, waiting for a reload.
 }
 
 // New creates a new mainModel that hols the ui state. It takes a new grid.
 // Check the core package.
-func New(config filesystem.Configuration, grid *field.Grid, bank *filesystem.Bank) tea.Model {
+func New(config filesystem.Configuration, grid *field.Grid, bank *filesystem.Bank, patternsDir string) tea.Model {
+	theme, themeErr := config.ResolveTheme()
+	applyTheme(theme)
+
 	ti := textinput.New()
-	ti.CharLimit = 10
+	ti.CharLimit = inputCharLimit
 	ti.Width = 12
-	ti.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("190"))
+	ti.Cursor.Style = lipgloss.NewStyle().Foreground(themeColor("highlight"))
 	model := mainModel{
 		bank:       bank,
 		grid:       grid,
@@ -95,7 +151,10 @@ func New(config filesystem.Configuration, grid *field.Grid, bank *filesystem.Ban
 		selectionX: 1,
 		selectionY: 1,
 
-		version: config.Version(),
+		version:     config.Version(),
+		configErr:   errors.Join(config.Err(), themeErr),
+		patternsDir: patternsDir,
+		recovery:    bank.Recovery(),
 	}
 	return model
 }
@@ -112,6 +171,29 @@ func blink() tea.Cmd {
 	})
 }
 
+func autosave() tea.Cmd {
+	return tea.Tick(autosaveFrequency, func(t time.Time) tea.Msg {
+		return autosaveMsg(t)
+	})
+}
+
+func watch() tea.Cmd {
+	return tea.Tick(watchFrequency, func(t time.Time) tea.Msg {
+		return watchMsg(t)
+	})
+}
+
+// saveLayers saves all the playing grids. Backups are only rotated at
+// startup, so autosaves never push the startup copy out.
+func saveLayers(m mainModel) tea.Cmd {
+	return func() tea.Msg {
+		for _, l := range m.grid.Layers() {
+			l.Save(m.bank)
+		}
+		return saveMsg(true)
+	}
+}
+
 func save(m mainModel) tea.Cmd {
 	return func() tea.Msg {
 		m.grid.Save(m.bank)
@@ -120,7 +202,7 @@ func save(m mainModel) tea.Cmd {
 }
 
 func (m mainModel) Init() tea.Cmd {
-	return tea.Batch(tea.EnterAltScreen, tick(), blink())
+	return tea.Batch(tea.EnterAltScreen, tick(), blink(), autosave(), watch())
 }
 
 func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -137,33 +219,129 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.input.Cursor.Blink = !m.input.Cursor.Blink
 		return m, blink()
 
+	case autosaveMsg:
+		if !m.grid.Playing {
+			return m, autosave()
+		}
+		return m, tea.Batch(autosave(), saveLayers(m))
+
+	case watchMsg:
+		if m.bank.Changed() {
+			m = m.reloadBank()
+		}
+		return m, watch()
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
+		if m.recovery != "" {
+			switch {
+			case key.Matches(msg, m.keymap.EditNode):
+				return m.recoverBank(), nil
+			case key.Matches(msg, m.keymap.Cancel):
+				m.recovery = ""
+				return m, nil
+			case key.Matches(msg, m.keymap.Quit):
+				break
+			default:
+				return m, nil
+			}
+		}
+
+		if len(m.reload) > 0 {
+			switch {
+			case key.Matches(msg, m.keymap.EditNode):
+				return m.acceptReload(), nil
+			case key.Matches(msg, m.keymap.Cancel):
+				m.bank.RejectReload()
+				m.reload = nil
+				return m, nil
+			case key.Matches(msg, m.keymap.Play, m.keymap.Quit):
+				break
+			default:
+				return m, nil
+			}
+		}
+
 		if m.input.Focused() {
 			var cmd tea.Cmd
 			switch {
 			case key.Matches(msg, m.keymap.EditNode):
 				m.input.Blur()
+				if m.command {
+					return m.runCommand(m.input.Value())
+				}
+				if m.mode == PATTERN {
+					return m.exportPattern(m.input.Value()), nil
+				}
+				if m.mode == BANK {
+					return m.submitBankInput(m.input.Value()), nil
+				}
+				if m.mode == MOVE {
+					return m.selectNodes(m.input.Value()), nil
+				}
 				m.activeParam().SetEditValue(m.input.Value())
 				return m, nil
-			case key.Matches(msg, m.keymap.Cancel, m.keymap.EditInput):
+			case key.Matches(msg, m.keymap.Cancel):
 				m.input.Blur()
+				m.command = false
 				return m, nil
+			case m.command && msg.Type == tea.KeyTab:
+				return m.completeCommand(), nil
 			case key.Matches(msg, m.keymap.Quit):
 				break
 			default:
+				m.completions = nil
 				m.input, cmd = m.input.Update(msg)
 				return m, cmd
 			}
 		}
 
 		switch {
+		case key.Matches(msg, m.keymap.Command) && m.mode == MOVE:
+			return m.focusCommand(), nil
 		case key.Matches(msg, m.keymap.EditInput):
-			if m.mode != EDIT {
+			if m.mode == BANK {
+				return m.focusBankInput(bankInputName), nil
+			}
+			if !m.editingParams() && m.mode != PATTERN {
 				return m, nil
 			}
+			m.input.CharLimit = inputCharLimit
 			m.input.Focus()
 			m.input.Reset()
 			return m, nil
+		case key.Matches(msg, m.keymap.SelectNodes):
+			if m.mode != MOVE && m.mode != EDIT {
+				return m, nil
+			}
+			m.mode = MOVE
+			m.input.CharLimit = inputCharLimit
+			m.input.Focus()
+			m.input.Reset()
+			if m.query != nil {
+				m.input.SetValue(m.query.String())
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.GridNotes):
+			if m.mode != BANK {
+				return m, nil
+			}
+			return m.focusBankInput(bankInputNotes), nil
+		case key.Matches(msg, m.keymap.SearchGrid):
+			if m.mode != BANK {
+				return m, nil
+			}
+			return m.focusBankInput(bankInputSearch), nil
+		case key.Matches(msg, m.keymap.GridColor):
+			if m.mode != BANK {
+				return m, nil
+			}
+			m.annotateGrid(m.selectedGrid, func(g *filesystem.Grid) {
+				g.Color = (g.Color + 1) % (colorTags + 1)
+			})
+			return m, nil
 		case key.Matches(msg, m.keymap.Play):
 			m.grid.TogglePlay()
 			return m, nil
@@ -173,11 +351,20 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.moveBankGrid(dir)
 				return m, nil
 			}
-			if m.mode == EDIT || m.mode == CONFIG {
+			if m.mode == PATTERN {
+				m.movePattern(dir)
+				return m, nil
+			}
+			if m.mode == MIXER {
+				m.moveMixerStrip(dir)
+				return m, nil
+			}
+			if m.editingParams() {
 				m.moveParam(dir)
 				return m, nil
 			}
 			m.blink = true
+			m.query = nil
 			m.cursorX, m.cursorY = moveCursor(
 				dir, 1, m.cursorX, m.cursorY,
 				0, m.grid.Width-1, 0, m.grid.Height-1,
@@ -191,10 +378,11 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case key.Matches(msg, m.keymap.SelectionUp, m.keymap.SelectionRight, m.keymap.SelectionDown, m.keymap.SelectionLeft):
 			dir := m.keymap.Direction(msg)
-			if m.mode == EDIT || m.mode == CONFIG {
+			if m.editingParams() {
 				m.handleParamAltEdit(dir)
 				return m, save(m)
 			}
+			m.query = nil
 			m.selectionX, m.selectionY = moveCursor(
 				dir, 1, m.selectionX, m.selectionY,
 				m.cursorX, m.grid.Width-1, m.cursorY, m.grid.Height-1,
@@ -221,26 +409,80 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.params = newParams
 			return m, save(m)
 		case key.Matches(msg, m.keymap.MuteNode):
+			if m.mode == BANK {
+				if l := m.grid.Layer(m.selectedGrid); l != nil {
+					l.ToggleMute()
+				}
+				return m, nil
+			}
+			if m.mode == MIXER {
+				m.toggleMixerStrip(music.StripMuted)
+				return m, nil
+			}
+			if m.query != nil {
+				for _, n := range m.selectedEmitters() {
+					if a, ok := n.(music.Audible); ok {
+						a.SetMute(!a.Muted())
+					}
+				}
+				return m, save(m)
+			}
 			m.grid.ToggleNodeMutes(m.cursorX, m.cursorY, m.selectionX, m.selectionY)
 			return m, save(m)
 		case key.Matches(msg, m.keymap.MuteAllNode):
 			m.grid.SetAllNodeMutes(!m.mute)
 			m.mute = !m.mute
 			return m, save(m)
+		case key.Matches(msg, m.keymap.Layer):
+			if m.mode == BANK {
+				return m.toggleLayer()
+			}
+			if m.mode != MOVE {
+				return m, nil
+			}
+			layers := m.grid.Layers()
+			for i, l := range layers {
+				if l == m.grid {
+					return m.editLayer(layers[(i+1)%len(layers)]), tea.WindowSize()
+				}
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.Solo):
+			switch m.mode {
+			case BANK:
+				if l := m.grid.Layer(m.selectedGrid); l != nil {
+					l.ToggleSolo()
+				}
+			case MIXER:
+				m.toggleMixerStrip(music.StripSolo)
+			case MOVE, EDIT:
+				if m.query != nil {
+					m.grid.ToggleSolos(m.selectedEmitters())
+					return m, nil
+				}
+				m.grid.ToggleNodeSolos(m.cursorX, m.cursorY, m.selectionX, m.selectionY)
+			}
+			return m, nil
 		case key.Matches(msg, m.keymap.RemoveNode):
 			if m.mode == BANK {
 				m.bank.ClearGrid(m.selectedGrid)
 				return m.loadGridFromBank(), tea.WindowSize()
 			}
+			if m.mode == MIXER {
+				m.clearMixer()
+				return m, nil
+			}
 			m.mode = MOVE
 			m.grid.RemoveNodes(m.cursorX, m.cursorY, m.selectionX, m.selectionY)
 			return m, save(m)
 		case key.Matches(msg, m.keymap.EditNode):
 			if m.mode == BANK {
-				m.mode = MOVE
-				return m.loadGridFromBank(), tea.WindowSize()
+				return m.switchGrid()
 			}
-			if m.mode == CONFIG {
+			if m.mode == PATTERN {
+				return m.importPattern()
+			}
+			if m.mode == CONFIG || m.mode == SONG || m.mode == MIXER {
 				m.mode = MOVE
 				return m, nil
 			}
@@ -304,11 +546,45 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, save(m)
 		case key.Matches(msg, m.keymap.Configuration):
 			m.mode = m.toggleMode(CONFIG)
-			m.params = param.NewParamsForMidi(m.grid)
+			m.params = append(
+				param.NewParamsForMidi(m.grid, m.bank),
+				param.NewParamsForProgression(m.grid),
+			)
+			m.param = 0
+			m.paramPage = 0
+			return m, nil
+		case key.Matches(msg, m.keymap.Song):
+			m.mode = m.toggleMode(SONG)
+			m.params = param.NewParamsForSong(m.bank)
 			m.param = 0
 			m.paramPage = 0
 			return m, nil
+		case key.Matches(msg, m.keymap.Patterns):
+			m.mode = m.toggleMode(PATTERN)
+			m.patterns, m.fileErr = filesystem.ListPatterns(m.patternsDir)
+			m.selectedPattern = min(m.selectedPattern, max(len(m.patterns)-1, 0))
+			return m, nil
+		case key.Matches(msg, m.keymap.Mixer):
+			m.mode = m.toggleMode(MIXER)
+			m.mixerStrip = min(m.mixerStrip, m.mixerStrips()-1)
+			return m, nil
+		case key.Matches(msg, m.keymap.NoteLog):
+			return m.toggleNotePane(), tea.WindowSize()
+		case key.Matches(msg, m.keymap.CopyText):
+			text, err := filesystem.EncodeText(m.grid.Export())
+			if err == nil {
+				err = clipboard.WriteAll(text)
+			}
+			if err != nil {
+				m.fileErr = fmt.Errorf("cannot copy grid as text: %w", err)
+			} else {
+				m.fileErr = nil
+			}
+			return m, nil
 		case key.Matches(msg, m.keymap.Copy):
+			if m.mode == PATTERN {
+				return m.copyPattern(), nil
+			}
 			if m.mode == BANK {
 				m.bankClipboard = m.bank.Grids[m.selectedGrid]
 				return m, nil
@@ -335,6 +611,7 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, save(m)
 		case key.Matches(msg, m.keymap.Cancel):
 			m.mode = MOVE
+			m.query = nil
 			m.selectionX = m.cursorX
 			m.selectionY = m.cursorY
 			m.help.ShowAll = false
@@ -342,6 +619,7 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keymap.FitGridToWindow):
 			m.cursorX, m.cursorY = 1, 1
 			m.selectionX, m.selectionY = m.cursorX, m.cursorY
+			m.sized = false
 			m.grid.Resize(m.viewport.Width, m.viewport.Height)
 			m.viewport.Update(m.cursorX, m.cursorY, m.grid.Width, m.grid.Height)
 			return m, save(m)
@@ -363,10 +641,20 @@ func (m mainModel) View() string {
 		Render(m.help.View(m.keymap))
 
 	paramHelp := ""
-	if m.mode == EDIT || m.mode == CONFIG {
+	if m.command && m.input.Focused() {
+		paramHelp = m.help.Styles.ShortDesc.
+			MarginLeft(13).
+			Render(m.commandHint())
+	} else if m.editingParams() {
 		paramHelp = m.help.Styles.ShortDesc.
 			MarginLeft(16).
 			Render(m.activeParam().Help())
+	} else if err := m.err(); err != nil {
+		paramHelp = errorStyle.Render(err.Error())
+	} else if m.mode == BANK {
+		paramHelp = m.help.Styles.ShortDesc.
+			MarginLeft(13).
+			Render(m.bank.Grid(m.selectedGrid).Notes)
 	}
 
 	if m.help.ShowAll {
@@ -391,6 +679,17 @@ func (m mainModel) View() string {
 	)
 }
 
+// err returns the bank, file or configuration error to show to the user.
+func (m mainModel) err() error {
+	if err := m.bank.Err(); err != nil {
+		return err
+	}
+	if m.fileErr != nil {
+		return m.fileErr
+	}
+	return m.configErr
+}
+
 func (m mainModel) handleParamEdit(dir string) {
 	if len(m.activeParamPage()) < m.param+1 {
 		return
@@ -448,12 +747,21 @@ func (m mainModel) renderGrid() string {
 	for y := m.viewport.offsetY; y < m.viewport.offsetY+m.viewport.Height; y++ {
 		var nodes []string
 		for x := m.viewport.offsetX; x < m.viewport.offsetX+m.viewport.Width; x++ {
-			nodes = append(nodes, m.renderNode(m.grid.Nodes()[y][x], x, y))
+			// The grid might have been resized by a queued grid before
+			// the viewport update.
+			var n common.Node
+			if y < len(m.grid.Nodes()) && x < len(m.grid.Nodes()[y]) {
+				n = m.grid.Nodes()[y][x]
+			}
+			nodes = append(nodes, m.renderNode(n, x, y))
 		}
 		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Left, nodes...))
 	}
-	lines = append(lines, m.renderControl())
-	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	grid := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	if m.notePane != noNotePane {
+		grid = lipgloss.JoinHorizontal(lipgloss.Top, grid, m.renderNotePane())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, grid, m.renderControl())
 }
 
 func (m *mainModel) moveParam(dir string) {
@@ -511,11 +819,167 @@ func (m *mainModel) moveBankGrid(dir string) {
 	}
 }
 
+// loadGridFromBank loads the selected bank grid for edition. A grid already
+// playing as a layer is reloaded in place.
 func (m mainModel) loadGridFromBank() mainModel {
-	m.bank.Active = m.selectedGrid
-	isPlaying := m.grid.Playing
-	m.grid.Load(m.bank.Active, m.bank.ActiveGrid())
-	m.grid.Playing = isPlaying
+	grid := m.grid
+	if l := m.grid.Layer(m.selectedGrid); l != nil {
+		grid = l
+	}
+	isPlaying := grid.Playing
+	grid.Load(m.selectedGrid, m.bank.Grid(m.selectedGrid))
+	grid.Playing = isPlaying
+	return m.editLayer(grid)
+}
+
+// selectNodes selects the nodes matching a query and edits them.
+func (m mainModel) selectNodes(text string) mainModel {
+	q, err := field.ParseQuery(text)
+	if err != nil {
+		m.fileErr = fmt.Errorf("cannot select nodes: %w", err)
+		return m
+	}
+	if len(m.grid.Select(q)) == 0 {
+		m.fileErr = fmt.Errorf("no node matches %s", q)
+		return m
+	}
+	m.fileErr = nil
+	m.query = &q
+	m.mode = EDIT
+	m.params = param.NewParamsForNodes(m.grid, m.selectedEmitters())
+	m.param = 0
+	m.paramPage = 0
+	return m
+}
+
+// switchGrid edits the selected bank grid. It is queued while playing,
+// unless it already plays as a layer.
+func (m mainModel) switchGrid() (tea.Model, tea.Cmd) {
+	if l := m.grid.Layer(m.selectedGrid); l != nil {
+		return m.editLayer(l), tea.WindowSize()
+	}
+	m.mode = MOVE
+	if m.grid.Playing && m.selectedGrid != m.bank.Active {
+		m.grid.Queue(m.selectedGrid)
+		return m, nil
+	}
+	return m.loadGridFromBank(), tea.WindowSize()
+}
+
+// focusBankInput starts editing a text field of the selected bank grid.
+func (m mainModel) focusBankInput(input bankInput) mainModel {
+	m.bankInput = input
+	m.input.CharLimit = inputCharLimit
+	m.input.Focus()
+	m.input.Reset()
+	switch input {
+	case bankInputName:
+		m.input.SetValue(m.bank.Grid(m.selectedGrid).Name)
+	case bankInputNotes:
+		m.input.CharLimit = notesCharLimit
+		m.input.SetValue(m.bank.Grid(m.selectedGrid).Notes)
+	}
+	return m
+}
+
+// submitBankInput applies the text typed in the bank view.
+func (m mainModel) submitBankInput(value string) mainModel {
+	switch m.bankInput {
+	case bankInputName:
+		m.annotateGrid(m.selectedGrid, func(g *filesystem.Grid) {
+			g.Name = strings.TrimSpace(value)
+		})
+	case bankInputNotes:
+		m.annotateGrid(m.selectedGrid, func(g *filesystem.Grid) {
+			g.Notes = strings.TrimSpace(value)
+		})
+	case bankInputSearch:
+		m.searchGrid(value)
+	}
+	return m
+}
+
+// annotateGrid changes the name, color or notes of a bank grid. Playing
+// grids are updated too, so that they keep the annotations when saved.
+func (m mainModel) annotateGrid(nb int, annotate func(g *filesystem.Grid)) {
+	l := m.grid.Layer(nb)
+	g := m.bank.Grid(nb)
+	if l != nil {
+		g = l.Export()
+	}
+	annotate(&g)
+	if l != nil {
+		l.Name, l.Color, l.Notes = g.Name, g.Color, g.Notes
+	}
+	m.bank.Save(nb, g)
+}
+
+// searchGrid selects the next bank grid whose name contains the query.
+func (m *mainModel) searchGrid(query string) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return
+	}
+	for i := 1; i <= maxGrids; i++ {
+		nb := (m.selectedGrid + i) % maxGrids
+		if strings.Contains(strings.ToLower(m.bank.Grid(nb).Name), query) {
+			m.selectedGrid = nb
+			m.fileErr = nil
+			return
+		}
+	}
+	m.fileErr = fmt.Errorf("no grid named %q", query)
+}
+
+// recoverBank replaces the unreadable bank with its newest backup and
+// reloads the active grid.
+func (m mainModel) recoverBank() mainModel {
+	m.recovery = ""
+	if err := m.bank.Recover(); err != nil {
+		m.fileErr = fmt.Errorf("cannot recover bank: %w", err)
+		return m
+	}
+	m.fileErr = nil
+	m.grid = m.grid.Root()
+	m.selectedGrid = m.bank.Active
+	return m.loadGridFromBank()
+}
+
+// reloadBank reloads the bank after an external change. Grids that are not
+// playing are reloaded right away, the playing ones wait for the user
+// confirmation.
+func (m mainModel) reloadBank() mainModel {
+	playing := []int{}
+	for _, l := range m.grid.Layers() {
+		playing = append(playing, l.BankIndex)
+	}
+	reload, err := m.bank.Reload(playing)
+	if err != nil {
+		return m
+	}
+	m.reload = reload
+	return m
+}
+
+// acceptReload loads the playing grids from the reloaded bank.
+func (m mainModel) acceptReload() mainModel {
+	m.bank.AcceptReload()
+	m.reload = nil
+	for _, l := range m.grid.Layers() {
+		isPlaying := l.Playing
+		l.Load(l.BankIndex, m.bank.Grid(l.BankIndex))
+		l.Playing = isPlaying
+	}
+	return m.editLayer(m.grid)
+}
+
+// editLayer switches the edited grid to one of the playing grids.
+func (m mainModel) editLayer(grid *field.Grid) mainModel {
+	m.grid = grid
+	m.bank.Active = grid.BankIndex
+	m.sized = false
+	m.query = nil
+	m.gridParams = param.NewParamsForGrid(grid)
 	m.cursorX = 1
 	m.cursorY = 1
 	m.selectionX = 1
@@ -526,24 +990,127 @@ func (m mainModel) loadGridFromBank() mainModel {
 	return m.windowResize(m.viewport.Width, m.viewport.Height)
 }
 
+func (m *mainModel) movePattern(dir string) {
+	switch dir {
+	case "up", "left":
+		if m.selectedPattern == 0 {
+			return
+		}
+		m.selectedPattern--
+	case "down", "right":
+		if m.selectedPattern >= len(m.patterns)-1 {
+			return
+		}
+		m.selectedPattern++
+	}
+}
+
+// readPattern reads the selected pattern file.
+func (m mainModel) readPattern() (filesystem.Grid, error) {
+	if m.selectedPattern >= len(m.patterns) {
+		return filesystem.Grid{}, fmt.Errorf("no pattern selected")
+	}
+	filename := filesystem.PatternFilename(m.patternsDir, m.patterns[m.selectedPattern])
+	grid, err := filesystem.ImportPattern(filename)
+	if err != nil {
+		return grid, fmt.Errorf("cannot import pattern %s: %w", filename, err)
+	}
+	return grid, nil
+}
+
+// importPattern adds the selected pattern nodes at the cursor.
+func (m mainModel) importPattern() (mainModel, tea.Cmd) {
+	grid, err := m.readPattern()
+	m.fileErr = err
+	if err != nil {
+		return m, nil
+	}
+	m.grid.Import(grid, m.cursorX, m.cursorY)
+	m.mode = MOVE
+	m.params = param.NewParamsForNodes(m.grid, m.selectedEmitters())
+	return m, save(m)
+}
+
+// copyPattern copies the selected pattern to the bank clipboard, ready to
+// be pasted in any bank slot.
+func (m mainModel) copyPattern() mainModel {
+	grid, err := m.readPattern()
+	m.fileErr = err
+	if err != nil {
+		return m
+	}
+	m.bankClipboard = grid
+	m.selectedGrid = m.bank.Active
+	m.mode = BANK
+	return m
+}
+
+// exportPattern writes the edited grid to a pattern file.
+func (m mainModel) exportPattern(name string) mainModel {
+	if name == "" {
+		return m
+	}
+	filename := filesystem.PatternFilename(m.patternsDir, name)
+	m.fileErr = filesystem.ExportPattern(filename, m.grid.Export())
+	if m.fileErr != nil {
+		m.fileErr = fmt.Errorf("cannot export pattern %s: %w", filename, m.fileErr)
+		return m
+	}
+	m.patterns, m.fileErr = filesystem.ListPatterns(m.patternsDir)
+	for i, p := range m.patterns {
+		if p == name {
+			m.selectedPattern = i
+		}
+	}
+	return m
+}
+
+// toggleLayer adds the selected bank grid as a layer, or removes it if it
+// is already playing.
+func (m mainModel) toggleLayer() (mainModel, tea.Cmd) {
+	l := m.grid.Layer(m.selectedGrid)
+	if l == nil {
+		m.grid.AddLayer(m.selectedGrid)
+		return m, nil
+	}
+	if !l.IsLayer() {
+		return m, nil
+	}
+	if l == m.grid {
+		m = m.editLayer(m.grid.Root())
+		m.mode = BANK
+	}
+	m.grid.RemoveLayer(m.selectedGrid)
+	return m, tea.WindowSize()
+}
+
+// handleBankMetaCommand updates the ui when the grid loaded a queued bank
+// grid, from a meta command or the song. The song edition is kept while the
+// song plays.
 func (m mainModel) handleBankMetaCommand() (mainModel, tea.Cmd) {
 	if m.grid.BankIndex == m.bank.Active {
 		return m, tick()
 	}
 	m.bank.Active = m.grid.BankIndex
-	m.grid.Load(m.bank.Active, m.bank.ActiveGrid())
-	m.grid.Playing = true
-	m.mode = MOVE
-	m.param = 0
-	m.paramPage = 0
+	m.sized = false
+	m.cursorX = 1
+	m.cursorY = 1
+	m.selectionX = 1
+	m.selectionY = 1
+	m.query = nil
+	if m.mode != SONG {
+		m.mode = MOVE
+		m.param = 0
+		m.paramPage = 0
+	}
 	return m.windowResize(m.viewport.Width, m.viewport.Height), tea.Batch(tea.WindowSize(), tick())
 }
 
 func (m mainModel) windowResize(width, height int) mainModel {
 	m.help.Width = width
-	m.viewport.Width = width / 2
+	m.viewport.Width = (width - m.sidePaneWidth()) / 2
 	m.viewport.Height = height - controlsHeight - 1
-	if m.viewport.Width > m.grid.Width || m.viewport.Height > m.grid.Height {
+	if !m.sized && (m.viewport.Width > m.grid.Width || m.viewport.Height > m.grid.Height) {
 		m.grid.Resize(m.viewport.Width, m.viewport.Height)
 	}
 	m.viewport.Update(m.cursorX, m.cursorY, m.grid.Width, m.grid.Height)
@@ -562,6 +1129,11 @@ func (m mainModel) windowResize(width, height int) mainModel {
 	return m
 }
 
+// editingParams returns true if the current mode edits parameters.
+func (m mainModel) editingParams() bool {
+	return m.mode == EDIT || m.mode == CONFIG || m.mode == SONG
+}
+
 func (m mainModel) toggleMode(mo mode) mode {
 	if m.mode == mo {
 		return MOVE